@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/models/grid"
+	"github.com/everettraven/buoy/pkg/charm/models/tabs"
+	"github.com/everettraven/buoy/pkg/types"
+)
+
+// layoutPanels returns the models that should each be shown in their own tab,
+// along with the section each of them belongs to. Panels that are part of a
+// layout are grouped into a single grid model that is placed where the first
+// of its panels would have been.
+func layoutPanels(dash *types.Dashboard, panelModels []tea.Model, keys grid.KeyMap, styles grid.Styles) ([]tea.Model, []string, error) {
+	panelIndex := map[string]int{}
+	for i, panel := range dash.Panels {
+		panelIndex[panel.Name] = i
+	}
+
+	// layoutAt maps the index of a panel to the layout
+	// that should be shown in its place, if any
+	layoutAt := map[int]tea.Model{}
+	layoutSection := map[int]string{}
+	inLayout := map[int]string{}
+	for _, layout := range dash.Layouts {
+		rows := []grid.Row{}
+		first := len(dash.Panels)
+		for _, layoutRow := range layout.Rows {
+			row := grid.Row{Ratio: layoutRow.Ratio}
+			for _, column := range layoutRow.Columns {
+				i, ok := panelIndex[column.Panel]
+				if !ok {
+					return nil, nil, fmt.Errorf("layout %q: unknown panel %q", layout.Name, column.Panel)
+				}
+				if other, ok := inLayout[i]; ok {
+					return nil, nil, fmt.Errorf("layout %q: panel %q is already part of layout %q", layout.Name, column.Panel, other)
+				}
+				inLayout[i] = layout.Name
+				first = min(first, i)
+				row.Panes = append(row.Panes, grid.Pane{Model: panelModels[i], Ratio: column.Ratio})
+			}
+			rows = append(rows, row)
+		}
+		if first == len(dash.Panels) {
+			return nil, nil, fmt.Errorf("layout %q: no panels", layout.Name)
+		}
+		layoutAt[first] = grid.New(layout.Name, keys, styles, rows...)
+		layoutSection[first] = layout.Section
+	}
+
+	tabModels := []tea.Model{}
+	sections := []string{}
+	for i, panel := range panelModels {
+		if layout, ok := layoutAt[i]; ok {
+			tabModels = append(tabModels, layout)
+			sections = append(sections, layoutSection[i])
+			continue
+		}
+		if _, ok := inLayout[i]; ok {
+			continue
+		}
+		tabModels = append(tabModels, panel)
+		sections = append(sections, dash.Panels[i].Section)
+	}
+	return tabModels, sections, nil
+}
+
+// sectionPanels groups the models that are in the same section into a single
+// tab group that is placed where the first model of the section would have been.
+// Models that are not in a section are shown in their own tab.
+func sectionPanels(tabModels []tea.Model, sections []string, keys tabs.TabberKeyMap, styles tabs.TabModelStyleOptions) []tea.Model {
+	sectionTabs := map[string][]tabs.Tab{}
+	for i, model := range tabModels {
+		section := sections[i]
+		if section == "" {
+			continue
+		}
+		tab := tabs.Tab{Model: model}
+		if namer, ok := model.(Namer); ok {
+			tab.Name = namer.Name()
+		}
+		sectionTabs[section] = append(sectionTabs[section], tab)
+	}
+
+	grouped := []tea.Model{}
+	added := map[string]bool{}
+	for i, model := range tabModels {
+		section := sections[i]
+		if section == "" {
+			grouped = append(grouped, model)
+			continue
+		}
+		if added[section] {
+			continue
+		}
+		added[section] = true
+		grouped = append(grouped, tabs.NewGroup(section, keys, styles, sectionTabs[section]...))
+	}
+	return grouped
+}
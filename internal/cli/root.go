@@ -8,16 +8,29 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"path/filepath"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/keymap"
 	"github.com/everettraven/buoy/pkg/charm/models/dashboard"
+	"github.com/everettraven/buoy/pkg/charm/models/grid"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/everettraven/buoy/pkg/charm/models/helpview"
+	"github.com/everettraven/buoy/pkg/charm/models/palette"
+	"github.com/everettraven/buoy/pkg/charm/models/splash"
+	"github.com/everettraven/buoy/pkg/charm/models/statusbar"
 	"github.com/everettraven/buoy/pkg/charm/models/tabs"
 	"github.com/everettraven/buoy/pkg/charm/styles"
 	"github.com/everettraven/buoy/pkg/factories/datastream"
 	"github.com/everettraven/buoy/pkg/factories/panel"
+	"github.com/everettraven/buoy/pkg/session"
 	"github.com/everettraven/buoy/pkg/types"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/yaml"
 )
@@ -31,23 +44,64 @@ var rootCommand = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("getting theme flag: %w", err)
 		}
-		return run(args[0], themePath)
+		keymapPath, err := cmd.Flags().GetString("keymap")
+		if err != nil {
+			return fmt.Errorf("getting keymap flag: %w", err)
+		}
+		noMouse, err := cmd.Flags().GetBool("no-mouse")
+		if err != nil {
+			return fmt.Errorf("getting no-mouse flag: %w", err)
+		}
+		fresh, err := cmd.Flags().GetBool("fresh")
+		if err != nil {
+			return fmt.Errorf("getting fresh flag: %w", err)
+		}
+		return run(args[0], themePath, keymapPath, noMouse, fresh)
 	},
 }
 
 func init() {
 	rootCommand.AddCommand(versionCommand)
 	rootCommand.Flags().String("theme", styles.DefaultThemePath, "path to theme file")
+	rootCommand.Flags().String("keymap", keymap.DefaultKeymapPath, "path to keymap file")
+	rootCommand.Flags().Bool("no-mouse", false, "disable mouse support so that text can be selected in the terminal")
+	rootCommand.Flags().Bool("fresh", false, "start without restoring the tab, scroll and search state of the last session")
+}
+
+type Namer interface {
+	Name() string
+}
+
+// Stateful is implemented by panels whose
+// state is saved and restored between sessions
+type Stateful interface {
+	SaveState() (json.RawMessage, error)
+	RestoreState(json.RawMessage) error
 }
 
-type ErrorSetter interface {
-	SetError(err error)
+// run shows the dashboard until it is quit, starting it again
+// whenever it is reloaded or switched to another context
+func run(path string, themePath string, keymapPath string, noMouse bool, fresh bool) error {
+	context := ""
+	for {
+		restart, err := runDashboard(path, themePath, keymapPath, noMouse, fresh, context)
+		if err != nil || restart == nil {
+			return err
+		}
+		// the session saved when quitting is restored on restart
+		fresh = false
+		context = restart.Context
+	}
 }
 
-func run(path string, themePath string) error {
+// runDashboard shows the dashboard connected to the kubeconfig
+// context, or the current context if empty. It returns how to
+// start the dashboard again if it quit to be restarted.
+func runDashboard(path string, themePath string, keymapPath string, noMouse bool, fresh bool, context string) (*dashboard.Restart, error) {
 	var raw []byte
 	var ext string
 	u, err := url.ParseRequestURI(path)
+	sessionKey := session.Key(path, err == nil)
 	if err != nil {
 		ext = filepath.Ext(path)
 		raw, err = os.ReadFile(path)
@@ -82,39 +136,32 @@ func run(path string, themePath string) error {
 		log.Fatalf("loading theme: %s", err)
 	}
 
-	p := panel.NewPanelFactory(theme)
+	keys, err := keymap.LoadKeymap(keymapPath)
+	if err != nil {
+		log.Fatalf("loading keymap: %s", err)
+	}
 
-	cfg := config.GetConfigOrDie()
-	df, err := datastream.NewDatastreamFactory(cfg)
+	p := panel.NewPanelFactory(theme, keys)
+
+	cfg, err := config.GetConfigWithContext(context)
 	if err != nil {
-		log.Fatalf("configuring datastream factory: %s", err)
+		log.Fatalf("getting kubeconfig for context %q: %s", context, err)
 	}
+	context, contexts := kubeContexts(context)
 
 	panelModels := []tea.Model{}
+	startModes := []string{}
 	for _, panel := range dash.Panels {
 		mod, err := p.ModelForPanel(panel)
 		if err != nil {
 			log.Fatalf("getting model for panel %q: %s", panel.Name, err)
 		}
-		panelModels = append(panelModels, mod)
-	}
-
-	for _, panel := range panelModels {
-		dataStream, err := df.DatastreamForModel(panel)
+		mode, err := startMode(dash, panel)
 		if err != nil {
-			if errSetter, ok := panel.(ErrorSetter); ok {
-				errSetter.SetError(err)
-				continue
-			} else {
-				log.Fatalf("getting datastream for model: %s", err)
-			}
+			log.Fatalf("getting start mode for panel %q: %s", panel.Name, err)
 		}
-		if dataStream == nil {
-			log.Printf("nil datastream returned for panel (%T)", panel)
-			continue
-		}
-
-		go dataStream.Run(make(<-chan struct{}))
+		panelModels = append(panelModels, mod)
+		startModes = append(startModes, mode)
 	}
 
 	dashboardStyles := dashboard.DashboardStyleOptions{
@@ -125,15 +172,180 @@ func run(path string, themePath string) error {
 			TabStyle:      theme.TabStyle(),
 			LeftArrow:     theme.TabLeftArrow,
 			RightArrow:    theme.TabRightArrow,
+			ActivityStyle: theme.ActivityStyle(),
+			WarningStyle:  theme.WarningStyle(),
+			ErrorStyle:    theme.ErrorStyle(),
 		},
 		DividerStyle: theme.TabGap(),
+		SplashStyle: splash.Styles{
+			Logo:  theme.SplashLogoStyle(),
+			Error: theme.ErrorStyle(),
+		},
+		PaletteStyle: palette.Styles{
+			Border:   theme.PaletteStyle(),
+			Selected: theme.PaletteMatchStyle(),
+			Match:    theme.PaletteMatchStyle(),
+			Binding:  theme.PaletteBindingStyle(),
+		},
+		HelpStyle: helpview.Styles{
+			Border: theme.PaletteStyle(),
+			Title:  theme.PaletteMatchStyle(),
+			Key:    theme.HelpKeyStyle(),
+			Desc:   theme.PaletteBindingStyle(),
+		},
+		StatusStyle: statusbar.Styles{
+			Bar:     theme.StatusBarStyle(),
+			Warning: theme.WarningStyle(),
+			Error:   theme.ErrorStyle(),
+		},
+	}
+	tabModels, sections, err := layoutPanels(dash, panelModels, keys.Grid, grid.Styles{
+		PaneStyle:        theme.PaneStyle(),
+		FocusedPaneStyle: theme.FocusedPaneStyle(),
+	})
+	if err != nil {
+		log.Fatalf("laying out panels: %s", err)
 	}
-	m := dashboard.New(dashboard.DefaultDashboardKeys, dashboardStyles, panelModels...)
-	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+	tabModels = sectionPanels(tabModels, sections, keys.Tabs, dashboardStyles.TabModelStyle)
+
+	m := dashboard.New(keys.DashboardKeyOptions(), dashboardStyles, tabModels...)
+	m.SetContexts(context, contexts)
+
+	sessionDir, err := session.DefaultDir()
+	if err != nil {
+		log.Fatalf("finding session directory: %s", err)
+	}
+	sessions := session.NewStore(sessionDir)
+	if !fresh {
+		state, err := sessions.Load(sessionKey)
+		if err != nil {
+			log.Printf("ignoring the last session: %s", err)
+		}
+		restoreSession(state, m, dash.Panels, panelModels)
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !noMouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	program := tea.NewProgram(m, opts...)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go setupDatastreams(program, cfg, context, dash.Panels, panelModels, startModes, stopCh)
+
+	if _, err := program.Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
-	return nil
+
+	if err := sessions.Save(sessionKey, saveSession(m, dash.Panels, panelModels)); err != nil {
+		log.Printf("saving session: %s", err)
+	}
+	return m.Restart(), nil
+}
+
+// restoreSession selects the saved tab and restores
+// the saved state of every panel that has any
+func restoreSession(state *session.State, m *dashboard.Dashboard, panels []types.Panel, panelModels []tea.Model) {
+	for i, panel := range panelModels {
+		stateful, ok := panel.(Stateful)
+		if !ok {
+			continue
+		}
+		raw, ok := state.Panels[panels[i].Name]
+		if !ok {
+			continue
+		}
+		if err := stateful.RestoreState(raw); err != nil {
+			log.Printf("ignoring the last session of panel %q: %s", panels[i].Name, err)
+		}
+	}
+	m.SelectTab(state.Tab)
+}
+
+// saveSession returns the selected tab and the state of every panel
+func saveSession(m *dashboard.Dashboard, panels []types.Panel, panelModels []tea.Model) *session.State {
+	state := &session.State{Tab: m.SelectedTab(), Panels: map[string]json.RawMessage{}}
+	for i, panel := range panelModels {
+		stateful, ok := panel.(Stateful)
+		if !ok {
+			continue
+		}
+		raw, err := stateful.SaveState()
+		if err != nil {
+			log.Printf("saving the session of panel %q: %s", panels[i].Name, err)
+			continue
+		}
+		state.Panels[panels[i].Name] = raw
+	}
+	return state
+}
+
+// setupDatastreams connects to the cluster and then concurrently sets up
+// and starts the datastream for every panel, reporting progress to the program
+func setupDatastreams(program *tea.Program, cfg *rest.Config, context string, panels []types.Panel, panelModels []tea.Model, startModes []string, stopCh <-chan struct{}) {
+	df, err := datastream.NewDatastreamFactory(cfg)
+	if err != nil {
+		program.Send(splash.ClusterStatusMsg{Status: splash.StatusFailed, Err: fmt.Errorf("configuring datastream factory: %w", err)})
+		return
+	}
+	program.Send(splash.ClusterStatusMsg{Status: splash.StatusReady})
+	program.Send(clusterInfo(cfg, context))
+
+	wg := &sync.WaitGroup{}
+	for i, panel := range panelModels {
+		wg.Add(1)
+		go func(i int, panel tea.Model) {
+			defer wg.Done()
+			dataStream, err := df.DatastreamForModel(panel)
+			if err != nil {
+				program.Send(helper.ErrorMsg{Panel: panel, Err: err})
+				program.Send(splash.PanelStatusMsg{Name: panels[i].Name, Status: splash.StatusFailed, Err: err})
+				return
+			}
+			if dataStream == nil {
+				program.Send(splash.PanelStatusMsg{Name: panels[i].Name, Status: splash.StatusFailed, Err: fmt.Errorf("nil datastream returned for panel (%T)", panel)})
+				return
+			}
+
+			startDatastream(dataStream, panel, startModes[i], stopCh)
+			program.Send(splash.PanelStatusMsg{Name: panels[i].Name, Status: splash.StatusReady})
+		}(i, panel)
+	}
+	wg.Wait()
+}
+
+// clusterInfo returns the kubeconfig context and the version of
+// the cluster. Anything that can't be found is left empty.
+func clusterInfo(cfg *rest.Config, context string) statusbar.ClusterInfoMsg {
+	info := statusbar.ClusterInfoMsg{Context: context}
+	if dc, err := discovery.NewDiscoveryClientForConfig(cfg); err == nil {
+		if version, err := dc.ServerVersion(); err == nil {
+			info.Version = version.GitVersion
+		}
+	}
+	return info
+}
+
+// kubeContexts returns the kubeconfig context that is used, which
+// is the current context unless context is set, and the names of
+// every context of the kubeconfig in order
+func kubeContexts(context string) (string, []string) {
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	raw, err := loader.RawConfig()
+	if err != nil {
+		return context, nil
+	}
+	if context == "" {
+		context = raw.CurrentContext
+	}
+	contexts := []string{}
+	for name := range raw.Contexts {
+		contexts = append(contexts, name)
+	}
+	slices.Sort(contexts)
+	return context, contexts
 }
 
 func Execute() {
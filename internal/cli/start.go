@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/factories/datastream"
+	"github.com/everettraven/buoy/pkg/types"
+)
+
+type VisibilityHookSetter interface {
+	SetVisibilityHooks(onShow, onHide func())
+}
+
+type HiddenPauser interface {
+	PauseWhenHidden() bool
+}
+
+// startMode returns the start mode for the panel,
+// falling back to the dashboard start mode if unset
+func startMode(dash *types.Dashboard, panel types.Panel) (string, error) {
+	mode := panel.Start
+	if mode == "" {
+		mode = dash.Start
+	}
+	switch mode {
+	case "", types.StartEager:
+		return types.StartEager, nil
+	case types.StartLazy:
+		return types.StartLazy, nil
+	default:
+		return "", fmt.Errorf("unknown start mode %q", mode)
+	}
+}
+
+// startDatastream runs the datastream for the panel. Lazy panels
+// only run their datastream the first time they are shown. Panels
+// that pause when hidden are always started lazily and have their
+// datastream paused and resumed as they are hidden and shown.
+func startDatastream(ds datastream.Datastream, panel tea.Model, mode string, stopCh <-chan struct{}) {
+	pauser, pause := ds.(datastream.Pauser)
+	if hp, ok := panel.(HiddenPauser); !ok || !hp.PauseWhenHidden() {
+		pause = false
+	}
+
+	setter, ok := panel.(VisibilityHookSetter)
+	if !ok || (mode != types.StartLazy && !pause) {
+		go ds.Run(stopCh)
+		return
+	}
+
+	// onShow can be run from both the goroutine setting the
+	// hooks and the program's, so the datastream is started once
+	once := &sync.Once{}
+	onShow := func() {
+		started := false
+		once.Do(func() {
+			started = true
+			go ds.Run(stopCh)
+		})
+		if !started && pause {
+			pauser.Resume()
+		}
+	}
+
+	var onHide func()
+	if pause {
+		onHide = pauser.Pause
+	}
+	setter.SetVisibilityHooks(onShow, onHide)
+}
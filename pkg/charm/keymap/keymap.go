@@ -0,0 +1,341 @@
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/everettraven/buoy/pkg/charm/models/dashboard"
+	"github.com/everettraven/buoy/pkg/charm/models/grid"
+	"github.com/everettraven/buoy/pkg/charm/models/helpview"
+	"github.com/everettraven/buoy/pkg/charm/models/palette"
+	"github.com/everettraven/buoy/pkg/charm/models/panels/logs"
+	"github.com/everettraven/buoy/pkg/charm/models/panels/table"
+	"github.com/everettraven/buoy/pkg/charm/models/tabs"
+	"sigs.k8s.io/yaml"
+)
+
+// Keymap is the collection of keybindings used throughout the UI.
+// This is the basis for customizable keybindings
+type Keymap struct {
+	Dashboard dashboard.DashboardKeyMap
+	Tabs      tabs.TabberKeyMap
+	Palette   palette.KeyMap
+	Help      helpview.KeyMap
+	Grid      grid.KeyMap
+	Table     table.KeyMap
+	Logs      logs.KeyMap
+}
+
+// Config is the contents of a keymap file
+type Config struct {
+	// Preset is the name of the keymap to start from before
+	// applying the bindings. One of default, vim or emacs.
+	Preset string `json:"preset,omitempty"`
+	// Bindings maps a scope, i.e table, to the actions within
+	// that scope, i.e viewModeToggle, to the keys they are
+	// bound to. An empty list of keys unbinds the action.
+	Bindings map[string]map[string][]string `json:"bindings,omitempty"`
+}
+
+const DefaultKeymapPath = "~/.config/buoy/keymap.yaml"
+
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetEmacs   = "emacs"
+)
+
+// presets are the bindings applied on
+// top of the default keymap for a preset
+var presets = map[string]map[string]map[string][]string{
+	PresetDefault: {},
+	PresetVim: {
+		"tabs": {
+			"sectionRight": {"alt+right", "alt+l"},
+			"sectionLeft":  {"alt+left", "alt+h"},
+		},
+		"grid": {
+			"focusNext": {"ctrl+n", "ctrl+w"},
+		},
+	},
+	PresetEmacs: {
+		"tabs": {
+			"sectionRight": {"alt+right", "alt+f"},
+			"sectionLeft":  {"alt+left", "alt+b"},
+		},
+		"palette": {
+			"up":    {"up", "ctrl+p"},
+			"down":  {"down", "ctrl+n"},
+			"close": {"esc", "ctrl+g"},
+		},
+		"help": {
+			"up":    {"up", "ctrl+p"},
+			"down":  {"down", "ctrl+n"},
+			"close": {"esc", "ctrl+g"},
+		},
+		"grid": {
+			"focusNext": {"ctrl+n", "alt+o"},
+		},
+		"table": {
+			"filter":      {"/", "ctrl+s"},
+			"clearFilter": {"esc", "ctrl+g"},
+		},
+		"logs": {
+			"search":       {"/", "ctrl+s"},
+			"quitSearch":   {"esc", "ctrl+g"},
+			"toggleStrict": {"alt+s"},
+		},
+	},
+}
+
+// Default returns the keymap used when no keymap file is provided
+func Default() Keymap {
+	return Keymap{
+		Dashboard: dashboard.DefaultDashboardKeys,
+		Tabs:      tabs.DefaultTabberKeys,
+		Palette:   palette.DefaultKeys,
+		Help:      helpview.DefaultKeys,
+		Grid:      grid.DefaultKeys,
+		Table:     table.DefaultKeys,
+		Logs:      logs.DefaultKeys,
+	}
+}
+
+// Preset returns the default keymap with the bindings of the preset applied
+func Preset(name string) (Keymap, error) {
+	k := Default()
+	if name == "" {
+		return k, nil
+	}
+	bindings, ok := presets[name]
+	if !ok {
+		return k, fmt.Errorf("unknown preset %q", name)
+	}
+	if err := k.Rebind(bindings); err != nil {
+		return k, fmt.Errorf("applying preset %q: %w", name, err)
+	}
+	return k, nil
+}
+
+func LoadKeymap(keymapPath string) (Keymap, error) {
+	keymapPath, err := expandHome(keymapPath)
+	if err != nil {
+		return Default(), fmt.Errorf("expanding keymap path: %w", err)
+	}
+	// If the specified keymap file doesn't exist, use the default keymap
+	if _, err := os.Stat(keymapPath); err != nil {
+		return Default(), nil
+	}
+
+	raw, err := os.ReadFile(keymapPath)
+	if err != nil {
+		return Default(), fmt.Errorf("reading keymap file: %w", err)
+	}
+
+	cfg := &Config{}
+	// yaml is a superset of json so both are accepted
+	err = yaml.Unmarshal(raw, cfg)
+	if err != nil {
+		return Default(), fmt.Errorf("unmarshalling keymap: %w", err)
+	}
+
+	return FromConfig(*cfg)
+}
+
+// FromConfig returns the keymap described by cfg, making
+// sure that no keys are bound to more than one action
+func FromConfig(cfg Config) (Keymap, error) {
+	k, err := Preset(cfg.Preset)
+	if err != nil {
+		return k, err
+	}
+	if err := k.Rebind(cfg.Bindings); err != nil {
+		return k, err
+	}
+	return k, k.Validate()
+}
+
+// DashboardKeyOptions returns the keymaps used by the dashboard
+func (k Keymap) DashboardKeyOptions() dashboard.DashboardKeyOptions {
+	return dashboard.DashboardKeyOptions{
+		Dashboard: k.Dashboard,
+		Tabber:    k.Tabs,
+		Palette:   k.Palette,
+		Help:      k.Help,
+	}
+}
+
+// scopes returns the keymap of every scope by name
+func (k *Keymap) scopes() map[string]any {
+	return map[string]any{
+		"dashboard": &k.Dashboard,
+		"tabs":      &k.Tabs,
+		"palette":   &k.Palette,
+		"help":      &k.Help,
+		"grid":      &k.Grid,
+		"table":     &k.Table,
+		"logs":      &k.Logs,
+	}
+}
+
+// Rebind binds the actions of each scope to the provided keys. The
+// keys shown in the help are updated to match the new bindings.
+func (k *Keymap) Rebind(bindings map[string]map[string][]string) error {
+	scopes := k.scopes()
+	errs := []error{}
+	for _, scope := range sortedKeys(bindings) {
+		keyMap, ok := scopes[scope]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown scope %q", scope))
+			continue
+		}
+		for _, action := range sortedKeys(bindings[scope]) {
+			binding := bindingFor(keyMap, action)
+			if binding == nil {
+				errs = append(errs, fmt.Errorf("unknown action %q in scope %q", action, scope))
+				continue
+			}
+			rebind(binding, bindings[scope][action])
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate returns an error for every key that is bound to more
+// than one action among the bindings that are active at the same
+// time. The dashboard and tab bindings are always active, alongside
+// the bindings of the visible panel or layout. The palette and help
+// receive every key while they are open so their bindings are checked
+// on their own, apart from the dashboard key that closes the help.
+// Tables only use their filter prompt keys while a filter is being
+// typed, so those are checked apart from the other table keys.
+func (k Keymap) Validate() error {
+	global := []namedKeyMap{{"dashboard", k.Dashboard}, {"tabs", k.Tabs}}
+	tableBrowsing := struct {
+		ViewModeToggle, InspectRow, SortNext, SortReverse, Filter, ClearFilter, ScrollLeft, ScrollRight, ToggleWrap key.Binding
+	}{
+		k.Table.ViewModeToggle, k.Table.InspectRow, k.Table.SortNext, k.Table.SortReverse, k.Table.Filter, k.Table.ClearFilter,
+		k.Table.ScrollLeft, k.Table.ScrollRight, k.Table.ToggleWrap,
+	}
+	tableFiltering := struct{ SubmitFilter, ClearFilter, ToggleRegex key.Binding }{
+		k.Table.SubmitFilter, k.Table.ClearFilter, k.Table.ToggleRegex,
+	}
+	panels := [][]namedKeyMap{
+		{{"table", tableBrowsing}},
+		{{"table", tableFiltering}},
+		{{"logs", k.Logs}},
+		{{"grid", k.Grid}, {"table", tableBrowsing}},
+		{{"grid", k.Grid}, {"table", tableFiltering}},
+		{{"grid", k.Grid}, {"logs", k.Logs}},
+	}
+
+	scopes := [][]namedKeyMap{
+		{{"palette", k.Palette}},
+		{{"dashboard", struct{ Help key.Binding }{k.Dashboard.Help}}, {"help", k.Help}},
+	}
+	for _, panel := range panels {
+		scopes = append(scopes, append(append([]namedKeyMap{}, global...), panel...))
+	}
+
+	errs := []error{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		for _, err := range conflicts(scope) {
+			// the global bindings are checked with
+			// every panel so only report them once
+			if !seen[err.Error()] {
+				seen[err.Error()] = true
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type namedKeyMap struct {
+	name   string
+	keyMap any
+}
+
+// conflicts returns an error for every key
+// bound to more than one action in keyMaps
+func conflicts(keyMaps []namedKeyMap) []error {
+	errs := []error{}
+	boundTo := map[string]string{}
+	for _, km := range keyMaps {
+		v := reflect.ValueOf(km.keyMap)
+		for i := 0; i < v.NumField(); i++ {
+			binding, ok := v.Field(i).Interface().(key.Binding)
+			if !ok || !binding.Enabled() {
+				continue
+			}
+			action := fmt.Sprintf("%s.%s", km.name, actionName(v.Type().Field(i).Name))
+			for _, k := range binding.Keys() {
+				if other, ok := boundTo[k]; ok && other != action {
+					errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s", k, other, action))
+					continue
+				}
+				boundTo[k] = action
+			}
+		}
+	}
+	return errs
+}
+
+// bindingFor returns the binding for action in the keyMap
+// pointed to by keyMap, or nil if there is no such action
+func bindingFor(keyMap any, action string) *key.Binding {
+	v := reflect.ValueOf(keyMap).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !strings.EqualFold(v.Type().Field(i).Name, action) {
+			continue
+		}
+		if binding, ok := v.Field(i).Addr().Interface().(*key.Binding); ok {
+			return binding
+		}
+	}
+	return nil
+}
+
+func rebind(binding *key.Binding, keys []string) {
+	if len(keys) == 0 {
+		binding.Unbind()
+		return
+	}
+	desc := binding.Help().Desc
+	binding.SetKeys(keys...)
+	binding.SetHelp(strings.Join(keys, "/"), desc)
+	binding.SetEnabled(true)
+}
+
+// actionName returns the name of an action as it
+// is written in a keymap file, i.e viewModeToggle
+func actionName(field string) string {
+	return strings.ToLower(field[:1]) + field[1:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
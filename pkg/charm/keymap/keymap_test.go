@@ -0,0 +1,107 @@
+package keymap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultKeymapIsValid(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+	for name := range presets {
+		t.Logf("preset %q", name)
+		k, err := Preset(name)
+		assert.NoError(t, err)
+		assert.NoError(t, k.Validate())
+	}
+}
+
+func TestRebind(t *testing.T) {
+	k, err := FromConfig(Config{Bindings: map[string]map[string][]string{
+		"table": {"viewModeToggle": {"enter", "v"}},
+		"logs":  {"ToggleStrict": {}},
+	}})
+	assert.NoError(t, err)
+
+	t.Log("rebound keys are shown in the help")
+	assert.Equal(t, []string{"enter", "v"}, k.Table.ViewModeToggle.Keys())
+	assert.Equal(t, "enter/v", k.Table.ViewModeToggle.Help().Key)
+	assert.Equal(t, "toggle viewing contents of selected resource", k.Table.ViewModeToggle.Help().Desc)
+
+	t.Log("an empty list of keys unbinds the action")
+	assert.False(t, k.Logs.ToggleStrict.Enabled())
+
+	t.Log("the defaults are left alone")
+	k = Default()
+	assert.Equal(t, []string{"v"}, k.Table.ViewModeToggle.Keys())
+
+	t.Log("unknown scopes and actions are errors")
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"tables": {"viewModeToggle": {"v"}},
+		"logs":   {"explode": {"x"}},
+	}})
+	assert.ErrorContains(t, err, `unknown scope "tables"`)
+	assert.ErrorContains(t, err, `unknown action "explode" in scope "logs"`)
+
+	t.Log("unknown presets are errors")
+	_, err = FromConfig(Config{Preset: "nano"})
+	assert.ErrorContains(t, err, `unknown preset "nano"`)
+}
+
+func TestValidate(t *testing.T) {
+	t.Log("panel keys can't conflict with the dashboard keys")
+	_, err := FromConfig(Config{Bindings: map[string]map[string][]string{
+		"table": {"viewModeToggle": {"q"}},
+	}})
+	assert.EqualError(t, err, `key "q" is bound to both dashboard.quit and table.viewModeToggle`)
+
+	t.Log("panels that are never shown together can share keys")
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"table": {"viewModeToggle": {"ctrl+s"}},
+	}})
+	assert.NoError(t, err)
+
+	t.Log("panels in a layout can't conflict with the layout keys")
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"grid": {"zoomToggle": {"/"}},
+	}})
+	assert.EqualError(t, err, "key \"/\" is bound to both grid.zoomToggle and table.filter\nkey \"/\" is bound to both grid.zoomToggle and logs.search")
+
+	t.Log("table keys can share keys with the keys used while typing a filter")
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"table": {"viewModeToggle": {"enter"}},
+	}})
+	assert.NoError(t, err)
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"table": {"viewModeToggle": {"/"}},
+	}})
+	assert.EqualError(t, err, `key "/" is bound to both table.viewModeToggle and table.filter`)
+
+	t.Log("the palette only conflicts with itself")
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"palette": {"close": {"q"}},
+	}})
+	assert.NoError(t, err)
+	_, err = FromConfig(Config{Bindings: map[string]map[string][]string{
+		"palette": {"close": {"enter"}},
+	}})
+	assert.EqualError(t, err, `key "enter" is bound to both palette.select and palette.close`)
+}
+
+func TestLoadKeymap(t *testing.T) {
+	t.Log("a missing keymap file uses the default keymap")
+	k, err := LoadKeymap(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, Default().Table.ViewModeToggle.Keys(), k.Table.ViewModeToggle.Keys())
+
+	t.Log("keymap files are read as yaml")
+	path := filepath.Join(t.TempDir(), "keymap.yaml")
+	err = os.WriteFile(path, []byte("preset: vim\nbindings:\n  table:\n    viewModeToggle: [enter]\n"), 0600)
+	assert.NoError(t, err)
+	k, err = LoadKeymap(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"enter"}, k.Table.ViewModeToggle.Keys())
+	assert.Equal(t, []string{"ctrl+n", "ctrl+w"}, k.Grid.FocusNext.Keys())
+}
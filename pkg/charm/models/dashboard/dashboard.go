@@ -1,70 +1,155 @@
 package dashboard
 
 import (
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/everettraven/buoy/pkg/charm/models/helpview"
+	"github.com/everettraven/buoy/pkg/charm/models/palette"
+	"github.com/everettraven/buoy/pkg/charm/models/splash"
+	"github.com/everettraven/buoy/pkg/charm/models/statusbar"
 	"github.com/everettraven/buoy/pkg/charm/models/tabs"
 )
 
 type DashboardKeyMap struct {
-	Help key.Binding
-	Quit key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+	Palette   key.Binding
+	JumpToTab key.Binding
+	Reload    key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k DashboardKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+	return []key.Binding{k.Help, k.Palette, k.Quit}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
 // key.Map interface.
 func (k DashboardKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Help, k.Quit},
+		{k.Help, k.Palette, k.JumpToTab, k.Reload, k.Quit},
 	}
 }
 
 var DefaultDashboardKeys = DashboardKeyMap{
 	Help: key.NewBinding(
 		key.WithKeys("ctrl+h"),
-		key.WithHelp("ctrl+h", "toggle help"),
+		key.WithHelp("ctrl+h", "show all keybindings"),
 	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q, ctrl+c", "quit"),
 	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "open the command palette"),
+	),
+	JumpToTab: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "jump to tab"),
+	),
+	Reload: key.NewBinding(
+		key.WithKeys("f5"),
+		key.WithHelp("f5", "reload the dashboard"),
+	),
+}
+
+// Restart is returned by Dashboard.Restart when the dashboard
+// quit so that it can be started again, i.e to reload it
+type Restart struct {
+	// Context is the kubeconfig context to connect to
+	Context string
+}
+
+// DashboardKeyOptions is the set of keymaps that can be used
+// to configure the keybindings used by the Dashboard model
+type DashboardKeyOptions struct {
+	Dashboard DashboardKeyMap
+	Tabber    tabs.TabberKeyMap
+	Palette   palette.KeyMap
+	Help      helpview.KeyMap
+}
+
+var DefaultDashboardKeyOptions = DashboardKeyOptions{
+	Dashboard: DefaultDashboardKeys,
+	Tabber:    tabs.DefaultTabberKeys,
+	Palette:   palette.DefaultKeys,
+	Help:      helpview.DefaultKeys,
 }
 
 type Namer interface {
 	Name() string
 }
 
+// Container is implemented by models
+// that are made up of multiple panels
+type Container interface {
+	Panels() []tea.Model
+}
+
+// panelNames returns the names of all the panels,
+// including the panels within any containers
+func panelNames(panels ...tea.Model) []string {
+	names := []string{}
+	for _, panel := range panels {
+		if container, ok := panel.(Container); ok {
+			names = append(names, panelNames(container.Panels()...)...)
+			continue
+		}
+		if namer, ok := panel.(Namer); ok {
+			names = append(names, namer.Name())
+		}
+	}
+	return names
+}
+
 // DashboardStyleOptions is the set of style options that can be
 // used to configure the styles used by the Dashboard model
 type DashboardStyleOptions struct {
 	TabModelStyle tabs.TabModelStyleOptions
 	DividerStyle  lipgloss.Style
+	SplashStyle   splash.Styles
+	PaletteStyle  palette.Styles
+	StatusStyle   statusbar.Styles
+	HelpStyle     helpview.Styles
 }
 
 // Dashboard is a tea.Model implementation
 // for viewing Kubernetes information based
 // on a declarative dashboard description
 type Dashboard struct {
-	tabber       *tabs.TabModel
-	width        int
-	help         help.Model
-	keys         DashboardKeyMap
-	dividerStyle lipgloss.Style
+	tabber        *tabs.TabModel
+	splash        *splash.Model
+	showSplash    bool
+	palette       *palette.Model
+	statusbar     *statusbar.Model
+	showPalette   bool
+	helpView      *helpview.Model
+	showHelp      bool
+	width         int
+	height        int
+	contentHeight int
+	help          help.Model
+	keys          DashboardKeyMap
+	tabKeys       tabs.TabberKeyMap
+	dividerStyle  lipgloss.Style
+	// context is the kubeconfig context the dashboard is
+	// connected to and contexts the ones it can switch to
+	context  string
+	contexts []string
+	restart  *Restart
 }
 
-func New(keys DashboardKeyMap, style DashboardStyleOptions, panels ...tea.Model) *Dashboard {
+func New(keys DashboardKeyOptions, style DashboardStyleOptions, panels ...tea.Model) *Dashboard {
 	tabset := []tabs.Tab{}
 	for _, panel := range panels {
 		if namer, ok := panel.(Namer); ok {
@@ -72,14 +157,20 @@ func New(keys DashboardKeyMap, style DashboardStyleOptions, panels ...tea.Model)
 		}
 	}
 	return &Dashboard{
-		tabber:       tabs.New(tabs.DefaultTabberKeys, style.TabModelStyle, tabset...),
+		tabber:       tabs.New(keys.Tabber, style.TabModelStyle, tabset...),
+		splash:       splash.New(style.SplashStyle, panelNames(panels...)...),
+		showSplash:   true,
+		palette:      palette.New(keys.Palette, style.PaletteStyle),
+		statusbar:    statusbar.New(style.StatusStyle),
+		helpView:     helpview.New(keys.Help, style.HelpStyle),
 		help:         help.New(),
-		keys:         keys,
+		keys:         keys.Dashboard,
+		tabKeys:      keys.Tabber,
 		dividerStyle: style.DividerStyle,
 	}
 }
 
-func (d *Dashboard) Init() tea.Cmd { return nil }
+func (d *Dashboard) Init() tea.Cmd { return tea.Batch(d.tabber.Init(), d.splash.Init()) }
 
 func (d *Dashboard) tick() tea.Cmd {
 	return tea.Tick(time.Millisecond, func(t time.Time) tea.Msg {
@@ -90,27 +181,227 @@ func (d *Dashboard) tick() tea.Cmd {
 func (d *Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case palette.SelectedMsg:
+		d.showPalette = false
+		if msg.Item.Action == nil {
+			return d, nil
+		}
+		return d, msg.Item.Action()
+	case palette.ClosedMsg:
+		d.showPalette = false
+		return d, nil
+	case helpview.ClosedMsg:
+		d.showHelp = false
+		return d, nil
+	case statusbar.ClusterInfoMsg:
+		d.statusbar.Update(msg)
+		return d, nil
 	case tea.KeyMsg:
+		// every key press goes to the palette while
+		// it is open so that it can be typed into
+		if d.showPalette {
+			_, cmd = d.palette.Update(msg)
+			return d, cmd
+		}
+		// the same goes for the help, which
+		// can also be closed with the help key
+		if d.showHelp {
+			if key.Matches(msg, d.keys.Help) {
+				d.showHelp = false
+				return d, nil
+			}
+			_, cmd = d.helpView.Update(msg)
+			return d, cmd
+		}
 		switch {
-		case key.Matches(msg, d.keys.Quit):
+		case key.Matches(msg, d.keys.Palette):
+			d.showSplash = false
+			d.showPalette = true
+			d.palette.Open(d.paletteItems()...)
+			return d, nil
+		case key.Matches(msg, d.keys.JumpToTab) && !d.showSplash && !d.tabber.CapturingInput():
+			// the nth key of the binding jumps to the nth tab
+			d.tabber.Select(slices.Index(d.keys.JumpToTab.Keys(), msg.String()))
+			return d, d.resize()
+		// letters are typed instead of quitting while typing
+		case key.Matches(msg, d.keys.Quit) && !(msg.Type == tea.KeyRunes && d.tabber.CapturingInput()):
 			return d, tea.Quit
 		case key.Matches(msg, d.keys.Help):
-			d.help.ShowAll = !d.help.ShowAll
+			d.openHelp()
+			return d, nil
+		case key.Matches(msg, d.keys.Reload):
+			return d, d.restartWith(d.context)
+		default:
+			// any other key dismisses the splash screen
+			if d.showSplash && !d.splash.Failed() {
+				d.showSplash = false
+				return d, nil
+			}
+		}
+		if d.showSplash {
+			return d, d.resize()
+		}
+	case tea.MouseMsg:
+		if d.showSplash || d.showPalette || d.showHelp {
+			return d, nil
 		}
 	case tea.WindowSizeMsg:
 		d.width = msg.Width
+		d.height = msg.Height
+		d.help.Width = msg.Width
+		d.splash.Update(msg)
+		d.statusbar.Update(msg)
+		d.contentHeight = d.availableHeight()
+		d.palette.Update(tea.WindowSizeMsg{Width: d.width, Height: d.contentHeight})
+		d.helpView.Update(tea.WindowSizeMsg{Width: d.width, Height: d.contentHeight})
+		d.tabber, cmd = d.tabber.Update(tea.WindowSizeMsg{Width: d.width, Height: d.contentHeight})
+		return d, tea.Batch(d.tick(), cmd)
+	case splash.ClusterStatusMsg, splash.PanelStatusMsg:
+		d.splash.Update(msg)
+		if d.splash.Done() {
+			d.showSplash = false
+		}
+		return d, nil
+	case spinner.TickMsg:
+		if d.showSplash {
+			_, cmd = d.splash.Update(msg)
+		}
+		return d, cmd
 	}
 
 	d.tabber, cmd = d.tabber.Update(msg)
-	return d, tea.Batch(d.tick(), cmd)
+	return d, tea.Batch(d.tick(), cmd, d.resize())
+}
+
+// resize resends the size of the content area to the tabs when
+// it has changed, i.e because a tab with a different status or
+// different keybindings was selected
+func (d *Dashboard) resize() tea.Cmd {
+	height := d.availableHeight()
+	if height == d.contentHeight {
+		return nil
+	}
+	d.contentHeight = height
+	d.palette.Update(tea.WindowSizeMsg{Width: d.width, Height: height})
+	d.helpView.Update(tea.WindowSizeMsg{Width: d.width, Height: height})
+	var cmd tea.Cmd
+	d.tabber, cmd = d.tabber.Update(tea.WindowSizeMsg{Width: d.width, Height: height})
+	return cmd
+}
+
+// availableHeight returns the height left for the tabs once
+// the divider, status bar and help have been accounted for
+func (d *Dashboard) availableHeight() int {
+	return max(0, d.height-lipgloss.Height(d.divider())-lipgloss.Height(d.statusbar.View())-lipgloss.Height(d.help.View(d.Help())))
+}
+
+func (d *Dashboard) divider() string {
+	return d.dividerStyle.Render(strings.Repeat(" ", max(0, d.width-2)))
 }
 
 func (d *Dashboard) View() string {
-	divider := d.dividerStyle.Render(strings.Repeat(" ", max(0, d.width-2)))
-	return lipgloss.JoinVertical(0, d.tabber.View(), divider, d.help.View(d.Help()))
+	if d.showSplash {
+		return d.splash.View()
+	}
+	content := d.tabber.View()
+	if d.showPalette {
+		content = d.palette.View()
+	}
+	if d.showHelp {
+		content = d.helpView.View()
+	}
+	d.statusbar.SetPanel(d.tabber.Focused())
+	return lipgloss.JoinVertical(0, content, d.divider(), d.statusbar.View(), d.help.View(d.Help()))
+}
+
+// SelectedTab returns the name of the selected tab. The
+// selected tab of a section is named after both, i.e
+// "section / tab", the same as in the palette.
+func (d *Dashboard) SelectedTab() string {
+	target, ok := d.tabber.SelectedTarget()
+	if !ok {
+		return ""
+	}
+	return target.Name
+}
+
+// SelectTab selects the tab with the name
+// returned by SelectedTab, if there is one
+func (d *Dashboard) SelectTab(name string) {
+	for _, target := range d.tabber.Targets() {
+		if target.Name == name {
+			d.tabber.SelectTarget(target)
+			return
+		}
+	}
+}
+
+// paletteItems returns an item for every tab
+// followed by an item for every dashboard command
+func (d *Dashboard) paletteItems() []palette.Item {
+	items := []palette.Item{}
+	for _, target := range d.tabber.Targets() {
+		target := target
+		items = append(items, palette.Item{
+			Title: target.Name,
+			Action: func() tea.Cmd {
+				d.tabber.SelectTarget(target)
+				return d.resize()
+			},
+		})
+	}
+
+	tabKeys := d.tabKeys
+	items = append(items,
+		palette.Item{Title: "Next tab", Binding: tabKeys.TabRight, Action: func() tea.Cmd {
+			d.tabber.NextTab()
+			return d.resize()
+		}},
+		palette.Item{Title: "Previous tab", Binding: tabKeys.TabLeft, Action: func() tea.Cmd {
+			d.tabber.PrevTab()
+			return d.resize()
+		}},
+		palette.Item{Title: "Next section", Binding: tabKeys.SectionRight, Action: func() tea.Cmd {
+			d.tabber.NextSection()
+			return d.resize()
+		}},
+		palette.Item{Title: "Previous section", Binding: tabKeys.SectionLeft, Action: func() tea.Cmd {
+			d.tabber.PrevSection()
+			return d.resize()
+		}},
+		palette.Item{Title: "Show all keybindings", Binding: d.keys.Help, Action: func() tea.Cmd {
+			d.openHelp()
+			return nil
+		}},
+		palette.Item{Title: "Reload", Binding: d.keys.Reload, Action: func() tea.Cmd {
+			return d.restartWith(d.context)
+		}},
+	)
+	if len(d.contexts) > 0 {
+		items = append(items, palette.Item{Title: "Switch context", Action: func() tea.Cmd {
+			d.showPalette = true
+			d.palette.Open(d.contextItems()...)
+			return nil
+		}})
+	}
+	items = append(items,
+		palette.Item{Title: "Quit", Binding: d.keys.Quit, Action: func() tea.Cmd {
+			return tea.Quit
+		}},
+	)
+	return items
 }
 
+// Help returns the keys that can be used right now. The keys
+// of the selected tab come first so that they are always
+// visible in the mini help view.
 func (d *Dashboard) Help() help.KeyMap {
+	if d.showPalette {
+		return d.palette.Help()
+	}
+	if d.showHelp {
+		return d.helpView.Help()
+	}
 	return helper.NewCompositeHelpKeyMap(
 		[]help.KeyMap{
 			d.tabber.Help(),
@@ -118,3 +409,52 @@ func (d *Dashboard) Help() help.KeyMap {
 		}...,
 	)
 }
+
+// openHelp shows every keybinding that can be used on the selected
+// tab grouped by scope, starting with the keys that can always be used
+func (d *Dashboard) openHelp() {
+	d.showSplash = false
+	d.showHelp = true
+	d.helpView.Open(helper.NewCompositeHelpKeyMap(
+		helper.NewScopedHelpKeyMap("global", d.keys),
+		helper.NewScopedHelpKeyMap("tabs", d.tabKeys),
+		d.tabber.Help(),
+	).Groups()...)
+}
+
+// contextItems returns an item for every kubeconfig
+// context that restarts the dashboard connected to it
+func (d *Dashboard) contextItems() []palette.Item {
+	items := []palette.Item{}
+	for _, context := range d.contexts {
+		context := context
+		title := context
+		if context == d.context {
+			title += " (current)"
+		}
+		items = append(items, palette.Item{Title: title, Action: func() tea.Cmd {
+			return d.restartWith(context)
+		}})
+	}
+	return items
+}
+
+// SetContexts sets the kubeconfig context the dashboard is
+// connected to and the contexts that it can switch to
+func (d *Dashboard) SetContexts(current string, contexts []string) {
+	d.context = current
+	d.contexts = contexts
+}
+
+// restartWith quits the program so that the
+// dashboard is started again using context
+func (d *Dashboard) restartWith(context string) tea.Cmd {
+	d.restart = &Restart{Context: context}
+	return tea.Quit
+}
+
+// Restart returns how the dashboard should be started again
+// once the program has quit, or nil if it should not be
+func (d *Dashboard) Restart() *Restart {
+	return d.restart
+}
@@ -5,7 +5,12 @@ import (
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/everettraven/buoy/pkg/charm/models/helpview"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/item"
+	"github.com/everettraven/buoy/pkg/charm/models/panels/logs"
+	"github.com/everettraven/buoy/pkg/charm/models/splash"
 	"github.com/everettraven/buoy/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,17 +24,170 @@ func TestDashboardUpdate(t *testing.T) {
 		}, viewport.New(10, 10), item.Styles{}),
 	}
 
-	d := New(DefaultDashboardKeys, DashboardStyleOptions{}, panels...)
+	d := New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
 
 	t.Log("WindowSizeUpdate")
 	d.Update(tea.WindowSizeMsg{Width: 50, Height: 50})
 	assert.Equal(t, 50, d.width)
+	assert.Equal(t, 50-lipgloss.Height(d.divider())-lipgloss.Height(d.statusbar.View())-lipgloss.Height(d.help.View(d.Help())), d.contentHeight)
 
-	t.Log("toggle detailed help")
+	t.Log("open the help")
 	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ctrl+h")})
-	assert.True(t, d.help.ShowAll)
+	assert.True(t, d.showHelp)
+	assert.Equal(t, 50-lipgloss.Height(d.divider())-lipgloss.Height(d.statusbar.View())-lipgloss.Height(d.help.View(d.Help())), d.contentHeight)
+
+	t.Log("keys are typed into the help while it is open")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.True(t, d.showHelp)
+
+	t.Log("close the help")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ctrl+h")})
+	assert.False(t, d.showHelp)
 
 	t.Log("quit the program")
 	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
 	assert.Equal(t, cmd(), tea.Quit())
 }
+
+func TestDashboardSplash(t *testing.T) {
+	panels := []tea.Model{
+		item.New(types.Item{
+			PanelBase: types.PanelBase{
+				Name: "test",
+			},
+		}, viewport.New(10, 10), item.Styles{}),
+	}
+
+	t.Log("splash is hidden once setup is done")
+	d := New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	assert.True(t, d.showSplash)
+	d.Update(splash.ClusterStatusMsg{Status: splash.StatusReady})
+	assert.True(t, d.showSplash)
+	d.Update(splash.PanelStatusMsg{Name: "test", Status: splash.StatusReady})
+	assert.False(t, d.showSplash)
+
+	t.Log("splash is dismissed by a key press")
+	d = New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	assert.False(t, d.showSplash)
+
+	t.Log("splash is not dismissed when the cluster can not be reached")
+	d = New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.Update(splash.ClusterStatusMsg{Status: splash.StatusFailed})
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	assert.True(t, d.showSplash)
+}
+
+func TestDashboardTabNavigation(t *testing.T) {
+	panels := []tea.Model{}
+	for _, name := range []string{"first", "second", "third"} {
+		panels = append(panels, item.New(types.Item{
+			PanelBase: types.PanelBase{
+				Name: name,
+			},
+		}, viewport.New(10, 10), item.Styles{}))
+	}
+	d := New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.showSplash = false
+
+	t.Log("number keys jump to a tab")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	assert.Equal(t, 2, d.tabber.Selected())
+
+	t.Log("number keys without a tab are ignored")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9")})
+	assert.Equal(t, 2, d.tabber.Selected())
+
+	t.Log("the palette jumps to the selected tab")
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	assert.True(t, d.showPalette)
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("second")})
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	d.Update(cmd())
+	assert.False(t, d.showPalette)
+	assert.Equal(t, 1, d.tabber.Selected())
+
+	t.Log("tabs are selected by name")
+	assert.Equal(t, "second", d.SelectedTab())
+	d.SelectTab("third")
+	assert.Equal(t, 2, d.tabber.Selected())
+	d.SelectTab("missing")
+	assert.Equal(t, 2, d.tabber.Selected())
+}
+
+func TestDashboardHelp(t *testing.T) {
+	panels := []tea.Model{
+		logs.New(logs.DefaultKeys, &types.Logs{PanelBase: types.PanelBase{Name: "logs"}}, logs.Styles{}),
+	}
+	d := New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.showSplash = false
+	d.Update(tea.WindowSizeMsg{Width: 80, Height: 60})
+
+	t.Log("the help is grouped by scope")
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	titles := []string{}
+	for _, group := range helper.NewCompositeHelpKeyMap(d.tabber.Help()).Groups() {
+		titles = append(titles, group.Title)
+	}
+	assert.Equal(t, []string{"logs", "logs: scrolling"}, titles)
+	assert.Contains(t, d.View(), "global")
+	assert.Contains(t, d.View(), "tabs")
+	assert.Contains(t, d.View(), "logs: scrolling")
+	d.Update(helpview.ClosedMsg{})
+	assert.False(t, d.showHelp)
+
+	t.Log("the mini help shows the keys of the current mode")
+	short := func() []string {
+		keys := []string{}
+		for _, binding := range d.Help().ShortHelp() {
+			keys = append(keys, binding.Help().Key)
+		}
+		return keys
+	}
+	assert.Contains(t, short(), "/")
+	assert.NotContains(t, short(), "enter")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	assert.Contains(t, short(), "enter")
+	assert.NotContains(t, short(), "/")
+
+	t.Log("letters bound to quit are typed into the search instead")
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd != nil {
+		assert.NotEqual(t, tea.Quit(), cmd())
+	}
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestDashboardRestart(t *testing.T) {
+	panels := []tea.Model{
+		item.New(types.Item{PanelBase: types.PanelBase{Name: "test"}}, viewport.New(10, 10), item.Styles{}),
+	}
+	d := New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.showSplash = false
+	d.SetContexts("dev", []string{"dev", "prod"})
+	assert.Nil(t, d.Restart())
+
+	t.Log("reloading quits so that the dashboard is started again")
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyF5})
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Equal(t, &Restart{Context: "dev"}, d.Restart())
+
+	t.Log("switching context lists the contexts in the palette")
+	d = New(DefaultDashboardKeyOptions, DashboardStyleOptions{}, panels...)
+	d.showSplash = false
+	d.SetContexts("dev", []string{"dev", "prod"})
+	d.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("switch context")})
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	d.Update(cmd())
+	assert.True(t, d.showPalette)
+	assert.Contains(t, d.palette.View(), "dev (current)")
+
+	t.Log("selecting a context restarts the dashboard connected to it")
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("prod")})
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = d.Update(cmd())
+	assert.Equal(t, tea.Quit(), cmd())
+	assert.Equal(t, &Restart{Context: "prod"}, d.Restart())
+}
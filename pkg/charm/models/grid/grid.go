@@ -0,0 +1,523 @@
+package grid
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/muesli/reflow/truncate"
+)
+
+type KeyMap struct {
+	FocusNext  key.Binding
+	ZoomToggle key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.FocusNext}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.FocusNext, k.ZoomToggle},
+	}
+}
+
+var DefaultKeys = KeyMap{
+	FocusNext: key.NewBinding(
+		key.WithKeys("ctrl+n"),
+		key.WithHelp("ctrl+n", "focus the next pane"),
+	),
+	ZoomToggle: key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "toggle zooming the focused pane"),
+	),
+}
+
+type Helper interface {
+	Help() help.KeyMap
+}
+
+type ActivityReporter interface {
+	Activity() (int, helper.Attention)
+	Seen()
+}
+
+type InputCapturer interface {
+	CapturingInput() bool
+}
+
+type Visibility interface {
+	Show()
+	Hide()
+}
+
+type Styles struct {
+	PaneStyle        lipgloss.Style
+	FocusedPaneStyle lipgloss.Style
+}
+
+// Pane is a single panel in the grid. Ratio
+// is the share of the row width given to the pane.
+type Pane struct {
+	Model tea.Model
+	Ratio int
+}
+
+// Row is a row of panes in the grid. Ratio
+// is the share of the grid height given to the row.
+type Row struct {
+	Panes []Pane
+	Ratio int
+}
+
+// Model is a tea.Model implementation
+// that shows multiple panels at once
+// by laying them out in rows and columns
+type Model struct {
+	name    string
+	rows    []Row
+	focused int
+	zoomed  bool
+	drag    *divider
+	keys    KeyMap
+	styles  Styles
+	width   int
+	height  int
+}
+
+func New(name string, keys KeyMap, styles Styles, rows ...Row) *Model {
+	return &Model{
+		name:   name,
+		rows:   rows,
+		keys:   keys,
+		styles: styles,
+	}
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, m.resize()
+	case tea.KeyMsg:
+		if len(m.Panels()) == 0 {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, m.keys.FocusNext):
+			m.focused = (m.focused + 1) % len(m.Panels())
+			if m.zoomed {
+				return m, m.resize()
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.ZoomToggle):
+			m.zoomed = !m.zoomed
+			return m, m.resize()
+		}
+		// key presses are only sent to the focused pane
+		pane := m.pane(m.focused)
+		var cmd tea.Cmd
+		pane.Model, cmd = pane.Model.Update(msg)
+		return m, cmd
+	case tea.MouseMsg:
+		return m, m.mouse(msg)
+	}
+
+	var cmd tea.Cmd
+	m.eachPane(func(_ int, pane *Pane) {
+		var tempCmd tea.Cmd
+		pane.Model, tempCmd = pane.Model.Update(msg)
+		cmd = tea.Batch(cmd, tempCmd)
+	})
+	return m, cmd
+}
+
+// resize sends every pane a tea.WindowSizeMsg
+// with the size of its content area
+func (m *Model) resize() tea.Cmd {
+	var cmd tea.Cmd
+	if m.zoomed {
+		pane := m.pane(m.focused)
+		pane.Model, cmd = pane.Model.Update(m.contentSize(m.width, m.height))
+		return cmd
+	}
+
+	heights := Split(m.height, m.rowRatios())
+	for i := range m.rows {
+		widths := Split(m.width, paneRatios(m.rows[i]))
+		for j := range m.rows[i].Panes {
+			var tempCmd tea.Cmd
+			pane := &m.rows[i].Panes[j]
+			pane.Model, tempCmd = pane.Model.Update(m.contentSize(widths[j], heights[i]))
+			cmd = tea.Batch(cmd, tempCmd)
+		}
+	}
+	return cmd
+}
+
+// contentSize returns the size available to the
+// content of a pane with the provided outer size
+func (m *Model) contentSize(width, height int) tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{
+		Width:  max(0, width-m.styles.PaneStyle.GetHorizontalFrameSize()),
+		Height: max(0, height-m.styles.PaneStyle.GetVerticalFrameSize()),
+	}
+}
+
+func (m *Model) View() string {
+	if len(m.Panels()) == 0 {
+		return ""
+	}
+	if m.zoomed {
+		return m.renderPane(m.focused, m.width, m.height)
+	}
+
+	heights := Split(m.height, m.rowRatios())
+	rows := []string{}
+	index := 0
+	for i, row := range m.rows {
+		widths := Split(m.width, paneRatios(row))
+		panes := []string{}
+		for j := range row.Panes {
+			panes = append(panes, m.renderPane(index, widths[j], heights[i]))
+			index++
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, panes...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *Model) renderPane(index, width, height int) string {
+	style := m.styles.PaneStyle
+	if index == m.focused {
+		style = m.styles.FocusedPaneStyle
+	}
+	size := m.contentSize(width, height)
+	content := clip(m.pane(index).Model.View(), size.Width, size.Height)
+	return style.Width(size.Width).Height(size.Height).Render(content)
+}
+
+// minPaneSize is the smallest width or height
+// a pane can be resized to by dragging a divider
+const minPaneSize = 3
+
+// divider is the border between two rows or two panes of a
+// row. pane is the index of the pane to the left of the divider
+// or -1 if it is the divider below the row.
+type divider struct {
+	row  int
+	pane int
+}
+
+// mouse handles mouse events. Pressing on the border between two
+// panes starts dragging it to resize them, otherwise the event is
+// sent to the pane under the mouse, which is also focused on a press.
+func (m *Model) mouse(msg tea.MouseMsg) tea.Cmd {
+	if len(m.Panels()) == 0 {
+		return nil
+	}
+	if m.zoomed {
+		return m.updatePane(m.focused, msg, 0, 0)
+	}
+
+	if m.drag != nil {
+		switch msg.Action {
+		case tea.MouseActionMotion:
+			m.dragTo(msg.X, msg.Y)
+			return m.resize()
+		case tea.MouseActionRelease:
+			m.drag = nil
+			return nil
+		}
+	}
+
+	if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+		if d, ok := m.dividerAt(msg.X, msg.Y); ok {
+			m.drag = &d
+			return nil
+		}
+	}
+
+	index, x, y, ok := m.paneAt(msg.X, msg.Y)
+	if !ok {
+		return nil
+	}
+	if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+		m.focused = index
+	}
+	return m.updatePane(index, msg, x, y)
+}
+
+// updatePane sends msg to the pane at index
+// relative to the pane's position at x, y
+func (m *Model) updatePane(index int, msg tea.MouseMsg, x, y int) tea.Cmd {
+	pane := m.pane(index)
+	style := m.styles.PaneStyle
+	if index == m.focused {
+		style = m.styles.FocusedPaneStyle
+	}
+	frameX, frameY := helper.FrameOffset(style)
+	var cmd tea.Cmd
+	pane.Model, cmd = pane.Model.Update(helper.TranslateMouse(msg, x+frameX, y+frameY))
+	return cmd
+}
+
+// paneAt returns the index and position of the pane at x, y
+func (m *Model) paneAt(x, y int) (index, paneX, paneY int, ok bool) {
+	heights := Split(m.height, m.rowRatios())
+	for i, row := range m.rows {
+		if y < paneY+heights[i] {
+			widths := Split(m.width, paneRatios(row))
+			for j := range row.Panes {
+				if x < paneX+widths[j] {
+					return index + j, paneX, paneY, x >= 0 && y >= 0
+				}
+				paneX += widths[j]
+			}
+			return 0, 0, 0, false
+		}
+		paneY += heights[i]
+		index += len(row.Panes)
+	}
+	return 0, 0, 0, false
+}
+
+// dividerAt returns the divider at x, y. The borders
+// on both sides of a divider count as the divider.
+func (m *Model) dividerAt(x, y int) (divider, bool) {
+	heights := Split(m.height, m.rowRatios())
+	top := 0
+	for i, row := range m.rows {
+		bottom := top + heights[i]
+		if i < len(m.rows)-1 && (y == bottom-1 || y == bottom) {
+			return divider{row: i, pane: -1}, true
+		}
+		if y >= top && y < bottom {
+			widths := Split(m.width, paneRatios(row))
+			left := 0
+			for j := 0; j < len(row.Panes)-1; j++ {
+				right := left + widths[j]
+				if x == right-1 || x == right {
+					return divider{row: i, pane: j}, true
+				}
+				left = right
+			}
+			return divider{}, false
+		}
+		top = bottom
+	}
+	return divider{}, false
+}
+
+// dragTo moves the divider being dragged to x, y. The
+// ratios of the affected row or panes are replaced with
+// their sizes so that the rest of the grid is unchanged.
+func (m *Model) dragTo(x, y int) {
+	if m.drag.pane < 0 {
+		heights := Split(m.height, m.rowRatios())
+		i := m.drag.row
+		resizePair(heights, i, y-sum(heights[:i])+1)
+		for k := range m.rows {
+			m.rows[k].Ratio = heights[k]
+		}
+		return
+	}
+
+	row := &m.rows[m.drag.row]
+	widths := Split(m.width, paneRatios(*row))
+	j := m.drag.pane
+	resizePair(widths, j, x-sum(widths[:j])+1)
+	for k := range row.Panes {
+		row.Panes[k].Ratio = widths[k]
+	}
+}
+
+// resizePair sets sizes[i] to size, taking the space from
+// or giving it to sizes[i+1] so that their total is unchanged
+func resizePair(sizes []int, i, size int) {
+	combined := sizes[i] + sizes[i+1]
+	if combined < 2*minPaneSize {
+		return
+	}
+	size = min(max(size, minPaneSize), combined-minPaneSize)
+	sizes[i] = size
+	sizes[i+1] = combined - size
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
+
+func (m *Model) Name() string {
+	return m.name
+}
+
+// Panels returns the models of every pane in the grid
+func (m *Model) Panels() []tea.Model {
+	panels := []tea.Model{}
+	m.eachPane(func(_ int, pane *Pane) {
+		panels = append(panels, pane.Model)
+	})
+	return panels
+}
+
+func (m *Model) Help() help.KeyMap {
+	helps := []help.KeyMap{helper.NewScopedHelpKeyMap("layout", m.keys)}
+	if pane := m.pane(m.focused); pane != nil {
+		if h, ok := pane.Model.(Helper); ok {
+			helps = append(helps, h.Help())
+		}
+	}
+	return helper.NewCompositeHelpKeyMap(helps...)
+}
+
+// Focused returns the model of the focused pane
+func (m *Model) Focused() tea.Model {
+	if pane := m.pane(m.focused); pane != nil {
+		return pane.Model
+	}
+	return nil
+}
+
+// CapturingInput returns whether or not the
+// focused pane is currently capturing text input
+func (m *Model) CapturingInput() bool {
+	if pane := m.pane(m.focused); pane != nil {
+		if capturer, ok := pane.Model.(InputCapturer); ok {
+			return capturer.CapturingInput()
+		}
+	}
+	return false
+}
+
+// Activity returns the combined activity of every pane
+func (m *Model) Activity() (int, helper.Attention) {
+	total := 0
+	attention := helper.AttentionNone
+	m.eachPane(func(_ int, pane *Pane) {
+		if reporter, ok := pane.Model.(ActivityReporter); ok {
+			count, level := reporter.Activity()
+			total += count
+			attention = max(attention, level)
+		}
+	})
+	return total, attention
+}
+
+// Seen resets the activity of every pane
+// since they are all visible at once
+func (m *Model) Seen() {
+	m.eachPane(func(_ int, pane *Pane) {
+		if reporter, ok := pane.Model.(ActivityReporter); ok {
+			reporter.Seen()
+		}
+	})
+}
+
+// Show notifies every pane that it is shown since
+// all panes are visible when the grid is visible
+func (m *Model) Show() {
+	m.eachPane(func(_ int, pane *Pane) {
+		if v, ok := pane.Model.(Visibility); ok {
+			v.Show()
+		}
+	})
+}
+
+// Hide notifies every pane that it is hidden
+func (m *Model) Hide() {
+	m.eachPane(func(_ int, pane *Pane) {
+		if v, ok := pane.Model.(Visibility); ok {
+			v.Hide()
+		}
+	})
+}
+
+func (m *Model) eachPane(f func(index int, pane *Pane)) {
+	index := 0
+	for i := range m.rows {
+		for j := range m.rows[i].Panes {
+			f(index, &m.rows[i].Panes[j])
+			index++
+		}
+	}
+}
+
+func (m *Model) pane(index int) *Pane {
+	var found *Pane
+	m.eachPane(func(i int, pane *Pane) {
+		if i == index {
+			found = pane
+		}
+	})
+	return found
+}
+
+func (m *Model) rowRatios() []int {
+	ratios := []int{}
+	for _, row := range m.rows {
+		ratios = append(ratios, row.Ratio)
+	}
+	return ratios
+}
+
+func paneRatios(row Row) []int {
+	ratios := []int{}
+	for _, pane := range row.Panes {
+		ratios = append(ratios, pane.Ratio)
+	}
+	return ratios
+}
+
+// Split divides total into parts proportional to the provided ratios.
+// Ratios less than 1 are treated as 1. Any remainder from rounding is
+// given to the first parts so that the parts always add up to total.
+func Split(total int, ratios []int) []int {
+	parts := make([]int, len(ratios))
+	if len(ratios) == 0 {
+		return parts
+	}
+	sum := 0
+	for _, ratio := range ratios {
+		sum += max(1, ratio)
+	}
+	used := 0
+	for i, ratio := range ratios {
+		parts[i] = total * max(1, ratio) / sum
+		used += parts[i]
+	}
+	for i := 0; used < total; i = (i + 1) % len(parts) {
+		parts[i]++
+		used++
+	}
+	return parts
+}
+
+// clip truncates content so that it fits within the provided width and height
+func clip(content string, width, height int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		if lipgloss.Width(line) > width {
+			lines[i] = truncate.String(line, uint(width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,133 @@
+package grid
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeModel struct {
+	name  string
+	size  tea.WindowSizeMsg
+	keys  []tea.KeyMsg
+	mouse []tea.MouseMsg
+}
+
+func (f *fakeModel) Init() tea.Cmd { return nil }
+func (f *fakeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		f.size = msg
+	case tea.KeyMsg:
+		f.keys = append(f.keys, msg)
+	case tea.MouseMsg:
+		f.mouse = append(f.mouse, msg)
+	}
+	return f, nil
+}
+func (f *fakeModel) View() string { return f.name }
+
+func testStyles() Styles {
+	return Styles{
+		PaneStyle:        lipgloss.NewStyle().Border(lipgloss.RoundedBorder()),
+		FocusedPaneStyle: lipgloss.NewStyle().Border(lipgloss.RoundedBorder()),
+	}
+}
+
+func TestSplit(t *testing.T) {
+	assert.Equal(t, []int{50, 50}, Split(100, []int{1, 1}))
+	assert.Equal(t, []int{67, 33}, Split(100, []int{2, 1}))
+	assert.Equal(t, []int{34, 33, 33}, Split(100, []int{0, 0, 0}))
+	assert.Equal(t, []int{}, Split(100, []int{}))
+}
+
+func TestGridResize(t *testing.T) {
+	top := &fakeModel{name: "top"}
+	left := &fakeModel{name: "left"}
+	right := &fakeModel{name: "right"}
+	g := New("test", DefaultKeys, testStyles(),
+		Row{Ratio: 1, Panes: []Pane{{Model: top}}},
+		Row{Ratio: 1, Panes: []Pane{{Model: left, Ratio: 3}, {Model: right, Ratio: 1}}},
+	)
+
+	g.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	assert.Equal(t, tea.WindowSizeMsg{Width: 98, Height: 18}, top.size)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 73, Height: 18}, left.size)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 23, Height: 18}, right.size)
+	assert.Equal(t, 40, lipgloss.Height(g.View()))
+	assert.Equal(t, 100, lipgloss.Width(g.View()))
+}
+
+func TestGridFocusAndZoom(t *testing.T) {
+	first := &fakeModel{name: "first"}
+	second := &fakeModel{name: "second"}
+	g := New("test", DefaultKeys, testStyles(),
+		Row{Panes: []Pane{{Model: first}, {Model: second}}},
+	)
+	g.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+
+	t.Log("keys are sent to the focused pane")
+	g.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Len(t, first.keys, 1)
+	assert.Len(t, second.keys, 0)
+
+	t.Log("focus the next pane")
+	g.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	assert.Equal(t, 1, g.focused)
+	g.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Len(t, first.keys, 1)
+	assert.Len(t, second.keys, 1)
+
+	t.Log("zoom the focused pane")
+	g.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	assert.True(t, g.zoomed)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 98, Height: 18}, second.size)
+	assert.Contains(t, g.View(), "second")
+	assert.NotContains(t, g.View(), "first")
+
+	t.Log("unzoom")
+	g.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	assert.False(t, g.zoomed)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 48, Height: 18}, second.size)
+
+	t.Log("focus wraps around")
+	g.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	assert.Equal(t, 0, g.focused)
+}
+
+func TestGridMouse(t *testing.T) {
+	left := &fakeModel{name: "left"}
+	right := &fakeModel{name: "right"}
+	bottom := &fakeModel{name: "bottom"}
+	g := New("test", DefaultKeys, testStyles(),
+		Row{Panes: []Pane{{Model: left}, {Model: right}}},
+		Row{Panes: []Pane{{Model: bottom}}},
+	)
+	g.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	t.Log("clicking a pane focuses it and sends it the click relative to its content")
+	g.Update(tea.MouseMsg{X: 60, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 1, g.focused)
+	assert.Equal(t, []tea.MouseMsg{{X: 9, Y: 4, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}}, right.mouse)
+
+	t.Log("dragging the divider between panes resizes them")
+	g.Update(tea.MouseMsg{X: 50, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 69, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 69, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	assert.Equal(t, tea.WindowSizeMsg{Width: 68, Height: 18}, left.size)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 28, Height: 18}, right.size)
+
+	t.Log("dragging the divider between rows resizes them")
+	g.Update(tea.MouseMsg{X: 10, Y: 20, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 10, Y: 29, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	g.Update(tea.MouseMsg{X: 10, Y: 29, Button: tea.MouseButtonLeft, Action: tea.MouseActionRelease})
+	assert.Equal(t, tea.WindowSizeMsg{Width: 68, Height: 28}, left.size)
+	assert.Equal(t, tea.WindowSizeMsg{Width: 98, Height: 8}, bottom.size)
+
+	t.Log("panes can not be dragged smaller than the minimum size")
+	g.Update(tea.MouseMsg{X: 69, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	g.Update(tea.MouseMsg{X: 99, Y: 5, Button: tea.MouseButtonLeft, Action: tea.MouseActionMotion})
+	assert.Equal(t, tea.WindowSizeMsg{Width: 1, Height: 28}, right.size)
+}
@@ -0,0 +1,57 @@
+package helper
+
+import (
+	"sync"
+	"time"
+)
+
+// Attention is how urgently new activity should be looked at
+type Attention int
+
+const (
+	AttentionNone Attention = iota
+	AttentionWarning
+	AttentionError
+)
+
+// ActivityTracker counts the updates a model received since
+// it was last seen. It is meant to be embedded in models so
+// that their parent model can show that they have new data.
+type ActivityTracker struct {
+	mutex     sync.Mutex
+	count     int
+	attention Attention
+	last      time.Time
+}
+
+// RecordActivity records an update with the provided attention level
+func (a *ActivityTracker) RecordActivity(attention Attention) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.count++
+	a.attention = max(a.attention, attention)
+	a.last = time.Now()
+}
+
+// LastActivity returns when the model last received an update
+func (a *ActivityTracker) LastActivity() time.Time {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.last
+}
+
+// Activity returns the number of updates since the model was
+// last seen and the highest attention level of those updates
+func (a *ActivityTracker) Activity() (int, Attention) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.count, a.attention
+}
+
+// Seen resets the activity of the model
+func (a *ActivityTracker) Seen() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.count = 0
+	a.attention = AttentionNone
+}
@@ -32,3 +32,86 @@ func (ch CompositeHelpKeyMap) FullHelp() [][]key.Binding {
 	}
 	return bindings
 }
+
+// HelpGroup is the set of keybindings of a scope
+type HelpGroup struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// Groups returns the keybindings of the expanded help grouped by
+// scope, including the groups of any nested CompositeHelpKeyMaps.
+// Keybindings without a scope are added to the previous group.
+func (ch CompositeHelpKeyMap) Groups() []HelpGroup {
+	groups := []HelpGroup{}
+	for _, h := range ch.helps {
+		if composite, ok := h.(*CompositeHelpKeyMap); ok {
+			groups = append(groups, composite.Groups()...)
+			continue
+		}
+		group := HelpGroup{}
+		if scoped, ok := h.(Scoper); ok {
+			group.Title = scoped.Scope()
+		}
+		for _, column := range h.FullHelp() {
+			for _, binding := range column {
+				if binding.Enabled() {
+					group.Bindings = append(group.Bindings, binding)
+				}
+			}
+		}
+		if len(group.Bindings) == 0 {
+			continue
+		}
+		if len(groups) > 0 && group.Title == "" {
+			groups[len(groups)-1].Bindings = append(groups[len(groups)-1].Bindings, group.Bindings...)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// Scoper is implemented by help keymaps that
+// belong to a scope, i.e a type of panel
+type Scoper interface {
+	Scope() string
+}
+
+// ScopedHelpKeyMap is a help.KeyMap that
+// is shown under the title of its scope
+type ScopedHelpKeyMap struct {
+	help.KeyMap
+	scope string
+}
+
+func NewScopedHelpKeyMap(scope string, keyMap help.KeyMap) *ScopedHelpKeyMap {
+	return &ScopedHelpKeyMap{KeyMap: keyMap, scope: scope}
+}
+
+func (s ScopedHelpKeyMap) Scope() string {
+	return s.scope
+}
+
+// BindingsHelpKeyMap is a help.KeyMap made up of a fixed set of
+// keybindings, i.e the keybindings that can be used in a mode
+type BindingsHelpKeyMap struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func NewBindingsHelpKeyMap(short []key.Binding, full ...[]key.Binding) *BindingsHelpKeyMap {
+	return &BindingsHelpKeyMap{short: short, full: full}
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (b BindingsHelpKeyMap) ShortHelp() []key.Binding {
+	return b.short
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (b BindingsHelpKeyMap) FullHelp() [][]key.Binding {
+	return b.full
+}
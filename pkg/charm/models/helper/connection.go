@@ -0,0 +1,45 @@
+package helper
+
+import "sync"
+
+// ConnectionState is the state of the connection
+// a panel uses to receive data from the cluster
+type ConnectionState string
+
+const (
+	ConnectionIdle         ConnectionState = ""
+	ConnectionSyncing      ConnectionState = "syncing"
+	ConnectionWatching     ConnectionState = "watching"
+	ConnectionReconnecting ConnectionState = "reconnecting"
+	ConnectionForbidden    ConnectionState = "forbidden"
+	ConnectionPaused       ConnectionState = "paused"
+	// ConnectionEnded is the state of connections to sources that
+	// won't send any more data, i.e the logs of a completed pod
+	ConnectionEnded ConnectionState = "ended"
+)
+
+// ConnectionTracker holds the state of the connection a model
+// receives data from. It is meant to be embedded in models so
+// that the state can be set by their datastream.
+type ConnectionTracker struct {
+	mutex sync.Mutex
+	state ConnectionState
+	err   error
+}
+
+// SetConnectionState sets the state of the connection
+// and the error that caused it, if any
+func (c *ConnectionTracker) SetConnectionState(state ConnectionState, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.state = state
+	c.err = err
+}
+
+// ConnectionState returns the state of the connection
+// and the error that caused it, if any
+func (c *ConnectionTracker) ConnectionState() (ConnectionState, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.state, c.err
+}
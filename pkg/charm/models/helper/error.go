@@ -0,0 +1,21 @@
+package helper
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// ErrorMsg reports an error that keeps a panel from showing its
+// data. It is sent to every panel and only handled by the panel
+// it is for, so that the panel isn't written to from outside
+// of the program's goroutine.
+type ErrorMsg struct {
+	Panel tea.Model
+	Err   error
+}
+
+// ErrorFor returns the error of msg if it is an ErrorMsg for panel
+func ErrorFor(msg tea.Msg, panel tea.Model) (error, bool) {
+	errMsg, ok := msg.(ErrorMsg)
+	if !ok || errMsg.Panel != panel {
+		return nil, false
+	}
+	return errMsg.Err, true
+}
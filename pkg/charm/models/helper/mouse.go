@@ -0,0 +1,22 @@
+package helper
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FrameOffset returns how far the content of a block rendered
+// with style is from the top left corner of the block
+func FrameOffset(style lipgloss.Style) (x, y int) {
+	x = style.GetMarginLeft() + style.GetBorderLeftSize() + style.GetPaddingLeft()
+	y = style.GetMarginTop() + style.GetBorderTopSize() + style.GetPaddingTop()
+	return x, y
+}
+
+// TranslateMouse returns msg with its position made
+// relative to a child model rendered at x, y
+func TranslateMouse(msg tea.MouseMsg, x, y int) tea.MouseMsg {
+	msg.X -= x
+	msg.Y -= y
+	return msg
+}
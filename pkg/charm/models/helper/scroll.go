@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ScrollRestorer scrolls a viewport back to an offset that
+// was saved in a previous session. Content arrives after
+// the offset is restored so scrolling waits until the
+// viewport has enough content to scroll that far.
+type ScrollRestorer struct {
+	offset  int
+	pending bool
+}
+
+// RestoreOffset sets the offset to scroll to once
+// the viewport has enough content
+func (s *ScrollRestorer) RestoreOffset(offset int) {
+	s.offset = offset
+	s.pending = offset > 0
+}
+
+// Restore scrolls vp to the saved offset if it can. Any
+// user input cancels the restore so that it doesn't
+// scroll away from where the user has scrolled to.
+func (s *ScrollRestorer) Restore(vp *viewport.Model, msg tea.Msg) {
+	if !s.pending {
+		return
+	}
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		s.pending = false
+		return
+	}
+	if vp.TotalLineCount()-vp.Height >= s.offset {
+		vp.SetYOffset(s.offset)
+		s.pending = false
+	}
+}
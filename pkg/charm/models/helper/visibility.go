@@ -0,0 +1,51 @@
+package helper
+
+import "sync"
+
+// VisibilityHooks holds the functions that should be run
+// when a model is shown or hidden. It is meant to be embedded
+// in models so that they can be notified by their parent model.
+type VisibilityHooks struct {
+	mutex   sync.Mutex
+	onShow  func()
+	onHide  func()
+	visible bool
+}
+
+// SetVisibilityHooks sets the functions that are run
+// when the model is shown or hidden. Either may be nil.
+// If the model is already visible, onShow is run immediately,
+// which may be at the same time as Show runs it, so onShow
+// must be safe to run concurrently.
+func (v *VisibilityHooks) SetVisibilityHooks(onShow, onHide func()) {
+	v.mutex.Lock()
+	v.onShow = onShow
+	v.onHide = onHide
+	visible := v.visible
+	v.mutex.Unlock()
+	if visible && onShow != nil {
+		onShow()
+	}
+}
+
+// Show runs the hook for when the model is shown
+func (v *VisibilityHooks) Show() {
+	v.mutex.Lock()
+	v.visible = true
+	onShow := v.onShow
+	v.mutex.Unlock()
+	if onShow != nil {
+		onShow()
+	}
+}
+
+// Hide runs the hook for when the model is hidden
+func (v *VisibilityHooks) Hide() {
+	v.mutex.Lock()
+	v.visible = false
+	onHide := v.onHide
+	v.mutex.Unlock()
+	if onHide != nil {
+		onHide()
+	}
+}
@@ -0,0 +1,212 @@
+package helpview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/muesli/reflow/truncate"
+)
+
+type KeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Close key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Close},
+	}
+}
+
+var DefaultKeys = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+k"),
+		key.WithHelp("↑/ctrl+k", "scroll up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+j"),
+		key.WithHelp("↓/ctrl+j", "scroll down"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close the help"),
+	),
+}
+
+// ClosedMsg is sent when the help is closed
+type ClosedMsg struct{}
+
+type Styles struct {
+	Border lipgloss.Style
+	Title  lipgloss.Style
+	Key    lipgloss.Style
+	Desc   lipgloss.Style
+}
+
+// Model is a tea.Model implementation that shows
+// every keybinding grouped by scope and filters
+// them by what is typed
+type Model struct {
+	input    textinput.Model
+	groups   []helper.HelpGroup
+	filtered []helper.HelpGroup
+	offset   int
+	keys     KeyMap
+	styles   Styles
+	width    int
+	height   int
+}
+
+func New(keys KeyMap, styles Styles) *Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to filter keybindings"
+	return &Model{
+		input:  input,
+		keys:   keys,
+		styles: styles,
+	}
+}
+
+// Open resets the help to show the provided groups
+func (m *Model) Open(groups ...helper.HelpGroup) {
+	m.groups = groups
+	m.input.SetValue("")
+	m.input.Focus()
+	m.filter()
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Help() help.KeyMap {
+	return m.keys
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll(0)
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Close):
+			m.input.Blur()
+			return m, func() tea.Msg { return ClosedMsg{} }
+		case key.Matches(msg, m.keys.Up):
+			m.scroll(-1)
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			m.scroll(1)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.filter()
+	return m, cmd
+}
+
+// filter keeps the groups whose title matches the input
+// and the keybindings whose keys or description do
+func (m *Model) filter() {
+	m.offset = 0
+	m.filtered = []helper.HelpGroup{}
+	term := strings.ToLower(m.input.Value())
+	for _, group := range m.groups {
+		if strings.Contains(strings.ToLower(group.Title), term) {
+			m.filtered = append(m.filtered, group)
+			continue
+		}
+		matched := helper.HelpGroup{Title: group.Title}
+		for _, binding := range group.Bindings {
+			if strings.Contains(strings.ToLower(binding.Help().Key), term) || strings.Contains(strings.ToLower(binding.Help().Desc), term) {
+				matched.Bindings = append(matched.Bindings, binding)
+			}
+		}
+		if len(matched.Bindings) > 0 {
+			m.filtered = append(m.filtered, matched)
+		}
+	}
+}
+
+// scroll moves the visible lines by delta, keeping
+// the last line at the bottom of the help
+func (m *Model) scroll(delta int) {
+	m.offset = max(0, min(m.offset+delta, len(m.lines())-m.visibleLines()))
+}
+
+// visibleLines returns the number of lines
+// of keybindings that fit below the input
+func (m *Model) visibleLines() int {
+	return max(1, m.height-m.styles.Border.GetVerticalFrameSize()-2)
+}
+
+// lines returns a line for the title of every
+// group followed by a line per keybinding
+func (m *Model) lines() []string {
+	keyWidth := 0
+	for _, group := range m.filtered {
+		for _, binding := range group.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.Help().Key))
+		}
+	}
+
+	lines := []string{}
+	for i, group := range m.filtered {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		title := group.Title
+		if title == "" {
+			title = "general"
+		}
+		lines = append(lines, m.styles.Title.Render(title))
+		for _, binding := range group.Bindings {
+			k := binding.Help().Key
+			lines = append(lines, "  "+m.styles.Key.Render(k+strings.Repeat(" ", keyWidth-lipgloss.Width(k)))+"  "+m.styles.Desc.Render(binding.Help().Desc))
+		}
+	}
+	return lines
+}
+
+func (m *Model) View() string {
+	width := max(0, m.width-m.styles.Border.GetHorizontalFrameSize())
+	lines := m.lines()
+	if len(lines) == 0 {
+		lines = []string{"no matching keybindings"}
+	}
+	end := min(len(lines), m.offset+m.visibleLines())
+	visible := []string{m.input.View(), ""}
+	for _, line := range lines[min(m.offset, end):end] {
+		if width > 0 {
+			line = truncate.StringWithTail(line, uint(width), "…")
+		}
+		visible = append(visible, line)
+	}
+
+	// lipgloss sizes include the padding but not the border or margins
+	style := m.styles.Border
+	if m.width > 0 && m.height > 0 {
+		style = style.
+			Width(max(0, m.width-style.GetHorizontalBorderSize()-style.GetHorizontalMargins())).
+			Height(max(0, m.height-style.GetVerticalBorderSize()-style.GetVerticalMargins()))
+	}
+	return style.Render(strings.Join(visible, "\n"))
+}
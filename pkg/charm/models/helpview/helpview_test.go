@@ -0,0 +1,78 @@
+package helpview
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func groups() []helper.HelpGroup {
+	return []helper.HelpGroup{
+		{Title: "global", Bindings: []key.Binding{
+			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+			key.NewBinding(key.WithKeys("ctrl+p"), key.WithHelp("ctrl+p", "open the command palette")),
+		}},
+		{Title: "logs", Bindings: []key.Binding{
+			key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "open a prompt to search logs")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "exit search mode")),
+		}},
+	}
+}
+
+func TestHelpFilter(t *testing.T) {
+	h := New(DefaultKeys, Styles{})
+	h.Open(groups()...)
+
+	t.Log("every group is shown without input")
+	assert.Len(t, h.filtered, 2)
+
+	t.Log("typing filters keybindings by key and description")
+	h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("search")})
+	assert.Len(t, h.filtered, 1)
+	assert.Equal(t, "logs", h.filtered[0].Title)
+	assert.Len(t, h.filtered[0].Bindings, 2)
+
+	h.Open(groups()...)
+	h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ctrl")})
+	assert.Len(t, h.filtered, 1)
+	assert.Equal(t, "global", h.filtered[0].Title)
+	assert.Len(t, h.filtered[0].Bindings, 1)
+
+	t.Log("matching the title of a group keeps all of its keybindings")
+	h.Open(groups()...)
+	h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("glob")})
+	assert.Len(t, h.filtered, 1)
+	assert.Len(t, h.filtered[0].Bindings, 2)
+
+	t.Log("nothing matches")
+	h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	assert.Contains(t, h.View(), "no matching keybindings")
+}
+
+func TestHelpScroll(t *testing.T) {
+	h := New(DefaultKeys, Styles{})
+	h.Open(groups()...)
+	// the input and a blank line leave room for 3 lines
+	h.Update(tea.WindowSizeMsg{Width: 40, Height: 5})
+
+	t.Log("scrolling stops once the last line is visible")
+	h.Update(tea.KeyMsg{Type: tea.KeyDown})
+	h.Update(tea.KeyMsg{Type: tea.KeyDown})
+	h.Update(tea.KeyMsg{Type: tea.KeyDown})
+	h.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 4, h.offset)
+	assert.Contains(t, h.View(), "exit search mode")
+
+	t.Log("scrolling stops at the top")
+	for i := 0; i < 10; i++ {
+		h.Update(tea.KeyMsg{Type: tea.KeyUp})
+	}
+	assert.Equal(t, 0, h.offset)
+
+	t.Log("closing sends a closed message")
+	_, cmd := h.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ClosedMsg{}, cmd())
+}
@@ -0,0 +1,235 @@
+package palette
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+type KeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Select key.Binding
+	Close  key.Binding
+}
+
+// ShortHelp returns keybindings to be shown in the mini help view. It's part
+// of the key.Map interface.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Select, k.Close}
+}
+
+// FullHelp returns keybindings for the expanded help view. It's part of the
+// key.Map interface.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Select, k.Close},
+	}
+}
+
+var DefaultKeys = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+k"),
+		key.WithHelp("↑/ctrl+k", "move up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+j"),
+		key.WithHelp("↓/ctrl+j", "move down"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "run the selected item"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "close the palette"),
+	),
+}
+
+// Item is an entry in the palette
+type Item struct {
+	Title string
+	// Binding is the keybinding that does the same as
+	// the item without the palette. It is optional.
+	Binding key.Binding
+	// Action is run when the item is selected
+	Action func() tea.Cmd
+}
+
+// SelectedMsg is sent when an item
+// of the palette has been selected
+type SelectedMsg struct {
+	Item Item
+}
+
+// ClosedMsg is sent when the palette
+// is closed without selecting an item
+type ClosedMsg struct{}
+
+type Styles struct {
+	Border   lipgloss.Style
+	Selected lipgloss.Style
+	Match    lipgloss.Style
+	Binding  lipgloss.Style
+}
+
+type match struct {
+	item    Item
+	indexes []int
+}
+
+// Model is a tea.Model implementation
+// that fuzzy finds an item from a list
+type Model struct {
+	input    textinput.Model
+	items    []Item
+	matches  []match
+	selected int
+	keys     KeyMap
+	styles   Styles
+	width    int
+	height   int
+}
+
+func New(keys KeyMap, styles Styles) *Model {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "type to search"
+	return &Model{
+		input:  input,
+		keys:   keys,
+		styles: styles,
+	}
+}
+
+// Open resets the palette to show the provided items
+func (m *Model) Open(items ...Item) {
+	m.items = items
+	m.input.SetValue("")
+	m.input.Focus()
+	m.filter()
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Help() help.KeyMap {
+	return m.keys
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Close):
+			m.input.Blur()
+			return m, func() tea.Msg { return ClosedMsg{} }
+		case key.Matches(msg, m.keys.Select):
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.input.Blur()
+			item := m.matches[m.selected].item
+			return m, func() tea.Msg { return SelectedMsg{Item: item} }
+		case key.Matches(msg, m.keys.Up):
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			if m.selected < len(m.matches)-1 {
+				m.selected++
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.filter()
+	return m, cmd
+}
+
+// filter updates the matches for the current input.
+// Items are kept in order when there is no input.
+func (m *Model) filter() {
+	m.selected = 0
+	m.matches = []match{}
+	term := m.input.Value()
+	if term == "" {
+		for _, item := range m.items {
+			m.matches = append(m.matches, match{item: item})
+		}
+		return
+	}
+
+	titles := []string{}
+	for _, item := range m.items {
+		titles = append(titles, item.Title)
+	}
+	for _, found := range fuzzy.Find(term, titles) {
+		m.matches = append(m.matches, match{item: m.items[found.Index], indexes: found.MatchedIndexes})
+	}
+}
+
+func (m *Model) View() string {
+	width := min(60, max(0, m.width-m.styles.Border.GetHorizontalFrameSize()))
+	// the input takes up the first line
+	visible := max(1, m.height-m.styles.Border.GetVerticalFrameSize()-1)
+	start := 0
+	if m.selected >= visible {
+		start = m.selected - visible + 1
+	}
+
+	lines := []string{m.input.View()}
+	for i := start; i < len(m.matches) && i < start+visible; i++ {
+		lines = append(lines, m.renderMatch(m.matches[i], i == m.selected, width))
+	}
+	if len(m.matches) == 0 {
+		lines = append(lines, "no matches")
+	}
+
+	out := m.styles.Border.Width(width).Render(strings.Join(lines, "\n"))
+	if m.width == 0 || m.height == 0 {
+		return out
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, out)
+}
+
+func (m *Model) renderMatch(mt match, selected bool, width int) string {
+	var title strings.Builder
+	// fuzzy reports byte indexes of the matched characters
+	for i, r := range mt.item.Title {
+		if matched(i, mt.indexes) {
+			title.WriteString(m.styles.Match.Render(string(r)))
+		} else {
+			title.WriteRune(r)
+		}
+	}
+
+	prefix := "  "
+	if selected {
+		prefix = m.styles.Selected.Render("> ")
+	}
+	line := prefix + title.String()
+	binding := m.styles.Binding.Render(mt.item.Binding.Help().Key)
+	gap := max(1, width-lipgloss.Width(line)-lipgloss.Width(binding))
+	return line + strings.Repeat(" ", gap) + binding
+}
+
+func matched(index int, matches []int) bool {
+	for _, i := range matches {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package palette
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func items(titles ...string) []Item {
+	items := []Item{}
+	for _, title := range titles {
+		items = append(items, Item{Title: title})
+	}
+	return items
+}
+
+func TestPaletteFilter(t *testing.T) {
+	p := New(DefaultKeys, Styles{})
+	p.Open(items("Deployments", "Pods", "Toggle help")...)
+
+	t.Log("all items are shown in order without input")
+	assert.Len(t, p.matches, 3)
+	assert.Equal(t, "Deployments", p.matches[0].item.Title)
+
+	t.Log("typing fuzzy matches the items")
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("pds")})
+	assert.Len(t, p.matches, 1)
+	assert.Equal(t, "Pods", p.matches[0].item.Title)
+
+	t.Log("opening the palette again clears the input")
+	p.Open(items("Deployments", "Pods")...)
+	assert.Len(t, p.matches, 2)
+}
+
+func TestPaletteSelect(t *testing.T) {
+	p := New(DefaultKeys, Styles{})
+	p.Open(items("Deployments", "Pods")...)
+
+	t.Log("moving the selection stops at the ends")
+	p.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 0, p.selected)
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, p.selected)
+
+	t.Log("selecting sends the selected item")
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, SelectedMsg{Item: Item{Title: "Pods"}}, cmd())
+
+	t.Log("closing sends a closed message")
+	_, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, ClosedMsg{}, cmd())
+
+	t.Log("selecting without matches does nothing")
+	p.Open(items("Deployments", "Pods")...)
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	_, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd)
+}
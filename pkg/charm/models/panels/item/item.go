@@ -2,13 +2,18 @@ package item
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"sync"
 
 	"github.com/alecthomas/chroma/quick"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 	"github.com/everettraven/buoy/pkg/types"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	apimachtypes "k8s.io/apimachinery/pkg/types"
@@ -22,11 +27,21 @@ type Styles struct {
 // Model is a tea.Model implementation
 // that represents an item panel
 type Model struct {
+	helper.VisibilityHooks
+	helper.ActivityTracker
+	helper.ConnectionTracker
 	viewport viewport.Model
 	mutex    *sync.Mutex
 	item     types.Item
 	theme    Styles
 	err      error
+	scroll   helper.ScrollRestorer
+}
+
+// State is the part of the item that is saved between sessions
+type State struct {
+	// Offset is the number of lines scrolled past
+	Offset int `json:"offset,omitempty"`
 }
 
 func New(item types.Item, viewport viewport.Model, theme Styles) *Model {
@@ -41,16 +56,50 @@ func New(item types.Item, viewport viewport.Model, theme Styles) *Model {
 func (m *Model) Init() tea.Cmd { return nil }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if err, ok := helper.ErrorFor(msg, m); ok {
+		m.SetError(err)
+		return m, nil
+	}
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height / 2
+		m.viewport.Height = msg.Height
 	}
+	m.mutex.Lock()
+	m.scroll.Restore(&m.viewport, msg)
+	m.mutex.Unlock()
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
+// SaveState returns how far the item has been scrolled
+func (m *Model) SaveState() (json.RawMessage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return json.Marshal(State{Offset: m.viewport.YOffset})
+}
+
+// RestoreState scrolls to the saved offset
+// once the item has enough content
+func (m *Model) RestoreState(raw json.RawMessage) error {
+	state := &State{}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return fmt.Errorf("unmarshalling item state: %w", err)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.scroll.RestoreOffset(state.Offset)
+	return nil
+}
+
+// Help returns the keys used to scroll the item
+func (m *Model) Help() help.KeyMap {
+	vk := m.viewport.KeyMap
+	return helper.NewScopedHelpKeyMap("item", helper.NewBindingsHelpKeyMap(nil,
+		[]key.Binding{vk.Up, vk.Down, vk.PageUp, vk.PageDown, vk.HalfPageUp, vk.HalfPageDown}))
+}
+
 func (m *Model) View() string {
 	if m.err != nil {
 		return m.err.Error()
@@ -61,6 +110,7 @@ func (m *Model) View() string {
 func (m *Model) SetContent(content string) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	m.RecordActivity(helper.AttentionNone)
 	// by default set the content as the plain string passed in
 	m.viewport.SetContent(content)
 
@@ -14,7 +14,7 @@ func TestItemUpdate(t *testing.T) {
 	item := New(types.Item{}, viewport.New(10, 10), Styles{})
 	item.Update(tea.WindowSizeMsg{Width: 50, Height: 50})
 	assert.Equal(t, 50, item.viewport.Width)
-	assert.Equal(t, 25, item.viewport.Height)
+	assert.Equal(t, 50, item.viewport.Height)
 }
 
 func TestItemViewWithError(t *testing.T) {
@@ -29,3 +29,17 @@ func TestViewWithContent(t *testing.T) {
 	item.SetContent("some content")
 	assert.Contains(t, item.View(), "some content")
 }
+
+func TestItemState(t *testing.T) {
+	item := New(types.Item{}, viewport.New(50, 5), Styles{})
+	assert.NoError(t, item.RestoreState([]byte(`{"offset":3}`)))
+
+	t.Log("the offset is restored once there is enough content")
+	item.SetContent("a\nb\nc\nd\ne\nf\ng\nh")
+	item.Update(nil)
+	assert.Equal(t, 3, item.viewport.YOffset)
+
+	raw, err := item.SaveState()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"offset":3}`, string(raw))
+}
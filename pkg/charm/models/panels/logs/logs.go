@@ -1,7 +1,9 @@
 package logs
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 	"github.com/everettraven/buoy/pkg/types"
 	"github.com/muesli/reflow/wrap"
 	"github.com/sahilm/fuzzy"
@@ -72,10 +75,14 @@ type Styles struct {
 // Model is a tea.Model implementation
 // that can be used to view logs
 type Model struct {
+	helper.VisibilityHooks
+	helper.ActivityTracker
+	helper.ConnectionTracker
 	viewport       viewport.Model
 	searchbar      textinput.Model
 	mutex          *sync.Mutex
 	content        string
+	lines          int
 	contentUpdated bool
 	mode           string
 	keys           KeyMap
@@ -83,6 +90,19 @@ type Model struct {
 	theme          Styles
 	log            *types.Logs
 	err            error
+	height         int
+	warningPattern *regexp.Regexp
+	errorPattern   *regexp.Regexp
+	scroll         helper.ScrollRestorer
+}
+
+// State is the part of the logs that is saved between sessions
+type State struct {
+	// Search is the submitted search term, if any
+	Search string `json:"search,omitempty"`
+	Strict bool   `json:"strict,omitempty"`
+	// Offset is the number of lines scrolled past
+	Offset int `json:"offset,omitempty"`
 }
 
 func New(keys KeyMap, log *types.Logs, theme Styles) *Model {
@@ -90,28 +110,62 @@ func New(keys KeyMap, log *types.Logs, theme Styles) *Model {
 	searchbar.Prompt = theme.SearchPrompt
 	searchbar.Placeholder = theme.SearchPlaceholder
 	vp := viewport.New(10, 10)
+	warningPattern, errorPattern, err := compilePatterns(log)
 	return &Model{
-		viewport:  vp,
-		searchbar: searchbar,
-		log:       log,
-		mutex:     &sync.Mutex{},
-		content:   "",
-		mode:      modeLogs,
-		keys:      keys,
-		theme:     theme,
+		viewport:       vp,
+		searchbar:      searchbar,
+		log:            log,
+		mutex:          &sync.Mutex{},
+		content:        "",
+		mode:           modeLogs,
+		keys:           keys,
+		theme:          theme,
+		err:            err,
+		warningPattern: warningPattern,
+		errorPattern:   errorPattern,
 	}
 }
 
+// ValidatePatterns returns an error if the
+// attention patterns of the logs panel are invalid
+func ValidatePatterns(log *types.Logs) error {
+	_, _, err := compilePatterns(log)
+	return err
+}
+
+func compilePatterns(log *types.Logs) (warningPattern *regexp.Regexp, errorPattern *regexp.Regexp, err error) {
+	if log == nil {
+		return nil, nil, nil
+	}
+	if log.WarningPattern != "" {
+		warningPattern, err = regexp.Compile(log.WarningPattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compiling warning pattern: %w", err)
+		}
+	}
+	if log.ErrorPattern != "" {
+		errorPattern, err = regexp.Compile(log.ErrorPattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compiling error pattern: %w", err)
+		}
+	}
+	return warningPattern, errorPattern, nil
+}
+
 func (m *Model) Init() tea.Cmd { return nil }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if err, ok := helper.ErrorFor(msg, m); ok {
+		m.SetError(err)
+		return m, nil
+	}
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height / 2
+		m.height = msg.Height
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, m.keys.Search):
@@ -140,6 +194,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// the search mode is shown below the
+	// viewport when viewing search results
+	m.viewport.Height = m.height
+	if m.mode == modeSearched {
+		m.viewport.Height = max(0, m.height-1)
+	}
+
 	if m.contentUpdated && m.mode == modeLogs {
 		m.viewport.SetContent(wrapLogs(m.content, m.viewport.Width))
 		m.contentUpdated = false
@@ -154,13 +215,44 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.viewport.SetContent(m.searchLogs())
 	}
 
+	m.scroll.Restore(&m.viewport, msg)
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
+// SaveState returns the submitted search, the search mode
+// and how far the logs have been scrolled
+func (m *Model) SaveState() (json.RawMessage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	state := State{Strict: m.strictSearch, Offset: m.viewport.YOffset}
+	if m.mode == modeSearched {
+		state.Search = m.searchbar.Value()
+	}
+	return json.Marshal(state)
+}
+
+// RestoreState shows the results of the saved search and
+// scrolls to the saved offset once there are enough logs
+func (m *Model) RestoreState(raw json.RawMessage) error {
+	state := &State{}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return fmt.Errorf("unmarshalling logs state: %w", err)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.strictSearch = state.Strict
+	if state.Search != "" {
+		m.mode = modeSearched
+		m.searchbar.SetValue(state.Search)
+	}
+	m.scroll.RestoreOffset(state.Offset)
+	return nil
+}
+
 func (m *Model) View() string {
-	if m.err != nil {
-		return m.err.Error()
+	if err := m.error(); err != nil {
+		return err.Error()
 	}
 
 	searchMode := "fuzzy"
@@ -186,15 +278,69 @@ func (m *Model) View() string {
 	return m.viewport.View()
 }
 
+// CapturingInput returns whether or not
+// a search term is currently being typed
+func (m *Model) CapturingInput() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.mode == modeSearching
+}
+
+// Help returns the keys of the logs followed by the keys
+// that can be used in the current mode. Only the keys of
+// the current mode are shown in the mini help view.
 func (m *Model) Help() help.KeyMap {
-	return m.keys
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	vk := m.viewport.KeyMap
+	scrolling := []key.Binding{vk.Up, vk.Down, vk.PageUp, vk.PageDown, vk.HalfPageUp, vk.HalfPageDown}
+
+	var modeHelp help.KeyMap
+	switch m.mode {
+	case modeSearching:
+		searching := []key.Binding{m.keys.SubmitSearch, m.keys.QuitSearch, m.keys.ToggleStrict}
+		modeHelp = helper.NewScopedHelpKeyMap("logs: typing a search",
+			helper.NewBindingsHelpKeyMap(searching, searching))
+	case modeSearched:
+		modeHelp = helper.NewScopedHelpKeyMap("logs: viewing search results",
+			helper.NewBindingsHelpKeyMap([]key.Binding{m.keys.Search, m.keys.QuitSearch, m.keys.ToggleStrict},
+				[]key.Binding{m.keys.Search, m.keys.QuitSearch, m.keys.ToggleStrict}, scrolling))
+	default:
+		modeHelp = helper.NewScopedHelpKeyMap("logs: scrolling",
+			helper.NewBindingsHelpKeyMap([]key.Binding{m.keys.Search}, []key.Binding{m.keys.Search}, scrolling))
+	}
+	return helper.NewCompositeHelpKeyMap(
+		helper.NewScopedHelpKeyMap("logs", m.keys),
+		modeHelp,
+	)
 }
 
 func (m *Model) AddContent(content string) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	m.content = strings.Join([]string{m.content, content}, "\n")
+	m.lines++
 	m.contentUpdated = true
+	m.RecordActivity(m.attention(content))
+}
+
+// Count returns the number of log lines received
+func (m *Model) Count() (int, string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.lines, "lines"
+}
+
+// attention returns the attention level of a new log line
+func (m *Model) attention(content string) helper.Attention {
+	switch {
+	case m.errorPattern != nil && m.errorPattern.MatchString(content):
+		return helper.AttentionError
+	case m.warningPattern != nil && m.warningPattern.MatchString(content):
+		return helper.AttentionWarning
+	default:
+		return helper.AttentionNone
+	}
 }
 
 func (m *Model) Name() string {
@@ -217,10 +363,24 @@ func (m *Model) Container() string {
 	return m.log.Container
 }
 
+func (m *Model) PauseWhenHidden() bool {
+	return m.log.PauseWhenHidden
+}
+
+// SetError shows err instead of the logs. It is
+// safe to call from the goroutine streaming the logs.
 func (m *Model) SetError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
 	m.err = err
 }
 
+func (m *Model) error() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.err
+}
+
 // searchLogs searches the logs for the term in the searchbar
 // and returns a string with the matching log lines
 // and the matched term highlighted. Uses fuzzy search
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/everettraven/buoy/pkg/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,7 +68,7 @@ func TestLogsWindowSizeUpdate(t *testing.T) {
 	logs := New(DefaultKeys, nil, Styles{})
 	logs.Update(tea.WindowSizeMsg{Width: 100, Height: 100})
 	assert.Equal(t, logs.viewport.Width, 100)
-	assert.Equal(t, logs.viewport.Height, 50)
+	assert.Equal(t, logs.viewport.Height, 100)
 }
 
 func TestLogsAddContent(t *testing.T) {
@@ -85,4 +87,77 @@ func TestLogsViewWithError(t *testing.T) {
 	logs.SetError(err)
 	assert.Equal(t, err, logs.err)
 	assert.Equal(t, err.Error(), logs.View())
+
+	t.Log("errors can be set while the logs are shown")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logs.SetError(errors.New("other error"))
+	}()
+	logs.View()
+	<-done
+	assert.Equal(t, "other error", logs.View())
+}
+
+func TestLogsActivity(t *testing.T) {
+	logs := New(DefaultKeys, &types.Logs{WarningPattern: "WARN", ErrorPattern: "ERROR|panic"}, Styles{})
+	assert.NoError(t, logs.err)
+
+	t.Log("new lines are counted as activity")
+	logs.AddContent("starting")
+	count, attention := logs.Activity()
+	assert.Equal(t, 1, count)
+	assert.Equal(t, helper.AttentionNone, attention)
+
+	t.Log("lines matching a pattern raise the attention")
+	logs.AddContent("WARN slow request")
+	logs.AddContent("ERROR failed request")
+	logs.AddContent("done")
+	count, attention = logs.Activity()
+	assert.Equal(t, 4, count)
+	assert.Equal(t, helper.AttentionError, attention)
+
+	t.Log("seeing the logs resets the activity")
+	logs.Seen()
+	count, attention = logs.Activity()
+	assert.Equal(t, 0, count)
+	assert.Equal(t, helper.AttentionNone, attention)
+
+	t.Log("invalid patterns are reported")
+	assert.Error(t, ValidatePatterns(&types.Logs{ErrorPattern: "("}))
+}
+
+func TestLogsState(t *testing.T) {
+	logs := New(DefaultKeys, nil, Styles{})
+	logs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	logs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("error")})
+	logs.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	logs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	t.Log("the submitted search is saved")
+	raw, err := logs.SaveState()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"search":"error","strict":true}`, string(raw))
+
+	t.Log("the saved search is shown when restored")
+	restored := New(DefaultKeys, nil, Styles{})
+	assert.NoError(t, restored.RestoreState(raw))
+	assert.Equal(t, modeSearched, restored.mode)
+	assert.Equal(t, "error", restored.searchbar.Value())
+	assert.True(t, restored.strictSearch)
+
+	t.Log("the offset is restored once there are enough logs")
+	restored = New(DefaultKeys, nil, Styles{})
+	restored.Update(tea.WindowSizeMsg{Width: 50, Height: 5})
+	assert.NoError(t, restored.RestoreState([]byte(`{"offset":10}`)))
+	for i := 0; i < 10; i++ {
+		restored.AddContent("line")
+	}
+	restored.Update(nil)
+	assert.Equal(t, 0, restored.viewport.YOffset)
+	for i := 0; i < 10; i++ {
+		restored.AddContent("line")
+	}
+	restored.Update(nil)
+	assert.Equal(t, 10, restored.viewport.YOffset)
 }
@@ -0,0 +1,108 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	tbl "github.com/evertras/bubble-table/table"
+)
+
+const (
+	// defaultPlaceholder is shown for objects that don't
+	// have the path of a column without a placeholder
+	defaultPlaceholder = "n/a"
+	// cellErrorMarker marks cells that couldn't be computed
+	cellErrorMarker = "⚠"
+)
+
+// missing is the value of a column
+// whose path doesn't exist in an object
+type missing struct{}
+
+func (missing) String() string {
+	return defaultPlaceholder
+}
+
+var missingValue = missing{}
+
+// cellError is the value of a cell that
+// couldn't be computed for an object
+type cellError struct {
+	err error
+}
+
+func (c cellError) String() string {
+	return fmt.Sprintf("error: %s", c.err)
+}
+
+// renderCell returns the value shown in the cell of column i
+// for a value extracted from an object. Missing values are
+// shown as the placeholder of the column and errors as a
+// marker followed by the first line of the error. Style rules
+// are checked against the value before it is formatted.
+func (m *Model) renderCell(i int, value interface{}, now time.Time) interface{} {
+	switch v := value.(type) {
+	case missing:
+		if placeholder := m.columns[i].Placeholder; placeholder != "" {
+			return placeholder
+		}
+		return defaultPlaceholder
+	case cellError:
+		message, _, _ := strings.Cut(v.err.Error(), "\n")
+		return tbl.NewStyledCell(fmt.Sprintf("%s %s", cellErrorMarker, message), m.styles.CellErrorStyle)
+	}
+	var style lipgloss.Style
+	matched := false
+	if i < len(m.cellStyles) {
+		style, matched = firstMatch(m.cellStyles[i], value)
+	}
+	if i < len(m.formats) && m.formats[i] != nil {
+		value = m.formats[i](value, now)
+	}
+	if matched {
+		return tbl.NewStyledCell(value, style)
+	}
+	return value
+}
+
+// cellErrors returns the number of cells of
+// the row that couldn't be computed
+func (r *RowInfo) cellErrors() int {
+	count := 0
+	for _, value := range r.values {
+		if _, ok := value.(cellError); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// inspect describes every cell of the row, including the
+// full errors of the cells that couldn't be computed
+func (m *Model) inspect(row *RowInfo, now time.Time) string {
+	width := 0
+	for _, column := range m.columns {
+		width = max(width, lipgloss.Width(column.Header))
+	}
+	header := lipgloss.NewStyle().Bold(true).Width(width + 2)
+	lines := []string{row.Identifier.String(), ""}
+	for i, column := range m.columns {
+		var text string
+		switch v := row.values[column.Header].(type) {
+		case cellError:
+			text = m.styles.CellErrorStyle.Render(fmt.Sprintf("%s %s", cellErrorMarker, v.err))
+		case missing:
+			text = fmt.Sprintf("%s (not found)", m.renderCell(i, v, now))
+		default:
+			cell := m.renderCell(i, v, now)
+			if styled, ok := cell.(tbl.StyledCell); ok {
+				cell = styled.Style.Render(fmt.Sprint(styled.Data))
+			}
+			text = fmt.Sprint(cell)
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, header.Render(column.Header), text))
+	}
+	return strings.Join(lines, "\n")
+}
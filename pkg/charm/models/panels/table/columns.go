@@ -0,0 +1,194 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/tidwall/gjson"
+)
+
+// simplePathRegex matches dot notation paths that only
+// traverse object fields and array indices. These paths can be
+// resolved by walking the object directly instead of using gjson.
+var simplePathRegex = regexp.MustCompile(`^[A-Za-z0-9_\-]+(\.[A-Za-z0-9_\-]+)*$`)
+
+// object is an object being added to the table. It ensures that
+// the object is marshalled to JSON at most once, regardless of
+// how many columns need the JSON representation.
+type object struct {
+	content map[string]interface{}
+	raw     []byte
+	err     error
+}
+
+func newObject(content map[string]interface{}) *object {
+	return &object{content: content}
+}
+
+func (o *object) json() ([]byte, error) {
+	if o.raw == nil && o.err == nil {
+		o.raw, o.err = json.Marshal(o.content)
+		if o.err != nil {
+			o.err = fmt.Errorf("error marshalling item to json: %w", o.err)
+		}
+	}
+	return o.raw, o.err
+}
+
+// columnExtractor extracts the value
+// of a column from an object
+type columnExtractor interface {
+	extract(obj *object) (interface{}, error)
+}
+
+// fieldPathExtractor resolves simple dot notation
+// paths by walking the unstructured object
+type fieldPathExtractor struct {
+	fields []string
+}
+
+func (f *fieldPathExtractor) extract(obj *object) (interface{}, error) {
+	var current interface{} = obj.content
+	for _, field := range f.fields {
+		switch val := current.(type) {
+		case map[string]interface{}:
+			next, ok := val[field]
+			if !ok {
+				return missingValue, nil
+			}
+			current = next
+		case []interface{}:
+			index, err := strconv.Atoi(field)
+			if err != nil || index < 0 || index >= len(val) {
+				return missingValue, nil
+			}
+			current = val[index]
+		default:
+			return missingValue, nil
+		}
+	}
+	return current, nil
+}
+
+// gjsonExtractor resolves any valid gjson path
+// against the JSON representation of the object
+type gjsonExtractor struct {
+	path string
+}
+
+func (g *gjsonExtractor) extract(obj *object) (interface{}, error) {
+	raw, err := obj.json()
+	if err != nil {
+		return nil, err
+	}
+	res := gjson.GetBytes(raw, g.path)
+	if !res.Exists() {
+		return missingValue, nil
+	}
+	return res.Value(), nil
+}
+
+// failedExtractor is used for columns that failed
+// to compile, failing the cell of every object
+type failedExtractor struct {
+	err error
+}
+
+func (f *failedExtractor) extract(*object) (interface{}, error) {
+	return nil, f.err
+}
+
+// ValidateColumns returns an error if any of the
+// provided columns can not be compiled
+func ValidateColumns(columns []buoytypes.Column) error {
+	_, err := compileColumns(columns)
+	_, formatErr := compileFormats(columns)
+	return errors.Join(err, formatErr)
+}
+
+func compileColumns(columns []buoytypes.Column) ([]columnExtractor, error) {
+	extractors := []columnExtractor{}
+	errs := []error{}
+	for _, column := range columns {
+		extractor, err := compileColumn(column)
+		if err != nil {
+			err = fmt.Errorf("column %q: %w", column.Header, err)
+			errs = append(errs, err)
+			// keeps the extractors aligned with the columns
+			extractor = &failedExtractor{err: err}
+		}
+		extractors = append(extractors, extractor)
+	}
+	return extractors, errors.Join(errs...)
+}
+
+// compileColumn compiles the expression of the
+// column if it has one, otherwise its path
+func compileColumn(column buoytypes.Column) (columnExtractor, error) {
+	if column.Expr != "" {
+		if column.Path != "" {
+			return nil, errors.New("only one of path or expr can be set")
+		}
+		return compileExpr(column.Expr, column.Format)
+	}
+	return compilePath(column.Path)
+}
+
+func compilePath(path string) (columnExtractor, error) {
+	if expr, ok := strings.CutPrefix(path, jsonPathPrefix); ok {
+		return compileJSONPath(expr)
+	}
+	if simplePathRegex.MatchString(path) {
+		return &fieldPathExtractor{fields: strings.Split(path, ".")}, nil
+	}
+	if err := validateGJSONPath(path); err != nil {
+		return nil, err
+	}
+	return &gjsonExtractor{path: path}, nil
+}
+
+// validateGJSONPath performs a best effort validation of the
+// syntax of a gjson path. gjson itself never errors on a malformed
+// path so this catches mistakes before they silently render "n/a".
+func validateGJSONPath(path string) error {
+	if strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.HasSuffix(path, "|") {
+		return fmt.Errorf("invalid path %q: path must not start or end with a separator", path)
+	}
+
+	closers := map[rune]rune{'(': ')', '[': ']', '{': '}'}
+	stack := []rune{}
+	escaped := false
+	var prev rune
+	for _, r := range path {
+		switch {
+		case escaped:
+			escaped = false
+			prev = 0
+			continue
+		case r == '\\':
+			escaped = true
+		case r == '(' || r == '[' || r == '{':
+			stack = append(stack, closers[r])
+		case r == ')' || r == ']' || r == '}':
+			if len(stack) == 0 || stack[len(stack)-1] != r {
+				return fmt.Errorf("invalid path %q: unexpected %q", path, r)
+			}
+			stack = stack[:len(stack)-1]
+		case r == '.' && prev == '.' && len(stack) == 0:
+			return fmt.Errorf("invalid path %q: empty path component", path)
+		}
+		prev = r
+	}
+	if escaped {
+		return fmt.Errorf("invalid path %q: trailing escape character", path)
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("invalid path %q: missing %q", path, stack[len(stack)-1])
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testPod() map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": "default",
+			"labels": map[string]interface{}{
+				"app": "foo",
+			},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "foo:latest"},
+				map[string]interface{}{"name": "sidecar", "image": "bar:latest"},
+			},
+		},
+		"status": map[string]interface{}{
+			"phase": "Running",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+}
+
+func TestCompilePath(t *testing.T) {
+	for _, tc := range []struct {
+		path     string
+		expected interface{}
+	}{
+		{path: "metadata.name", expected: "foo"},
+		{path: "metadata.labels", expected: map[string]interface{}{"app": "foo"}},
+		{path: "spec.containers.1.name", expected: "sidecar"},
+		{path: "spec.containers.2.name", expected: missingValue},
+		{path: "status.phase.foo", expected: missingValue},
+		{path: "spec.containers.#.name", expected: []interface{}{"app", "sidecar"}},
+		{path: "status.conditions.#(type==Ready).status", expected: "True"},
+	} {
+		t.Log(tc.path)
+		extractor, err := compilePath(tc.path)
+		assert.NoError(t, err)
+		val, err := extractor.extract(newObject(testPod()))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, val)
+	}
+}
+
+func TestCompilePathSelectsFastPath(t *testing.T) {
+	extractor, err := compilePath("spec.containers.0.name")
+	assert.NoError(t, err)
+	assert.IsType(t, &fieldPathExtractor{}, extractor)
+
+	extractor, err = compilePath("spec.containers.#.name")
+	assert.NoError(t, err)
+	assert.IsType(t, &gjsonExtractor{}, extractor)
+}
+
+func TestCompilePathEmpty(t *testing.T) {
+	extractor, err := compilePath("")
+	assert.NoError(t, err)
+	val, err := extractor.extract(newObject(testPod()))
+	assert.NoError(t, err)
+	assert.Equal(t, missingValue, val)
+}
+
+func TestCompilePathInvalid(t *testing.T) {
+	for _, path := range []string{
+		".metadata.name",
+		"metadata.name.",
+		"metadata..name",
+		"status.conditions.#(type==Ready",
+		"status.conditions.#(type==Ready))",
+		"metadata.name\\",
+	} {
+		t.Log(path)
+		_, err := compilePath(path)
+		assert.Error(t, err)
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	assert.NoError(t, ValidateColumns([]buoytypes.Column{
+		{Header: "Name", Path: "metadata.name"},
+		{Header: "Ready", Path: "status.conditions.#(type==Ready).status"},
+	}))
+	assert.Error(t, ValidateColumns([]buoytypes.Column{
+		{Header: "Name", Path: "metadata.name"},
+		{Header: "Broken", Path: "status..phase"},
+	}))
+}
+
+func TestInvalidColumnKeepsColumnsAligned(t *testing.T) {
+	table := New(DefaultKeys, &buoytypes.Table{Columns: []buoytypes.Column{
+		{Header: "Name", Path: "metadata.name"},
+		{Header: "Broken", Expr: "self.metadata.name +"},
+		{Header: "Phase", Path: "status.phase"},
+	}}, Styles{})
+	assert.Error(t, table.err)
+
+	u := &unstructured.Unstructured{Object: testPod()}
+	u.SetUID(types.UID("foo"))
+	table.AddOrUpdate(u)
+	values := table.rows[u.GetUID()].values
+	assert.Equal(t, "foo", values["Name"])
+	assert.IsType(t, cellError{}, values["Broken"])
+	assert.Equal(t, "Running", values["Phase"])
+}
+
+func TestObjectMarshalledOnce(t *testing.T) {
+	obj := newObject(testPod())
+	first, err := obj.json()
+	assert.NoError(t, err)
+	second, err := obj.json()
+	assert.NoError(t, err)
+	assert.Same(t, &first[0], &second[0])
+}
+
+var benchmarkColumns = []buoytypes.Column{
+	{Header: "Namespace", Path: "metadata.namespace"},
+	{Header: "Name", Path: "metadata.name"},
+	{Header: "App", Path: "metadata.labels.app"},
+	{Header: "Phase", Path: "status.phase"},
+	{Header: "First Container", Path: "spec.containers.0.name"},
+	{Header: "First Image", Path: "spec.containers.0.image"},
+	{Header: "Second Container", Path: "spec.containers.1.name"},
+	{Header: "Second Image", Path: "spec.containers.1.image"},
+	{Header: "Containers", Path: "spec.containers.#.name"},
+	{Header: "Ready", Path: "status.conditions.#(type==Ready).status"},
+}
+
+// BenchmarkExtractMarshalPerColumn measures the previous approach of
+// marshalling the whole object for every column as a baseline
+func BenchmarkExtractMarshalPerColumn(b *testing.B) {
+	pod := testPod()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, column := range benchmarkColumns {
+			raw, err := json.Marshal(pod)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = gjson.GetBytes(raw, column.Path).Value()
+		}
+	}
+}
+
+func BenchmarkExtractCompiled(b *testing.B) {
+	pod := testPod()
+	extractors, err := compileColumns(benchmarkColumns)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		obj := newObject(pod)
+		for _, extractor := range extractors {
+			if _, err := extractor.extract(obj); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkAddOrUpdate(b *testing.B) {
+	table := New(DefaultKeys, &buoytypes.Table{Columns: benchmarkColumns}, Styles{})
+	objs := []*unstructured.Unstructured{}
+	for i := 0; i < 1000; i++ {
+		u := &unstructured.Unstructured{Object: testPod()}
+		u.SetName(fmt.Sprintf("pod-%d", i))
+		u.SetUID(types.UID(fmt.Sprintf("pod-%d", i)))
+		objs = append(objs, u)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.AddOrUpdate(objs[i%len(objs)])
+	}
+}
+
+func BenchmarkAddOrUpdateSorted(b *testing.B) {
+	columns := slices.Clone(benchmarkColumns)
+	columns[0].Width = buoytypes.ColumnWidthAuto
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "Ready"}}}, Styles{})
+	objs := []*unstructured.Unstructured{}
+	for i := 0; i < 1000; i++ {
+		u := &unstructured.Unstructured{Object: testPod()}
+		u.SetName(fmt.Sprintf("pod-%d", i))
+		u.SetUID(types.UID(fmt.Sprintf("pod-%d", i)))
+		objs = append(objs, u)
+	}
+	for _, u := range objs {
+		table.AddOrUpdate(u)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.AddOrUpdate(objs[i%len(objs)])
+	}
+}
+
+// BenchmarkResync measures updating every row of a large
+// table, as happens when its informer resyncs, and then
+// showing the updated rows once
+func BenchmarkResync(b *testing.B) {
+	columns := slices.Clone(benchmarkColumns)
+	columns[0].Width = buoytypes.ColumnWidthAuto
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "Ready"}}}, Styles{})
+	objs := []*unstructured.Unstructured{}
+	for i := 0; i < 10000; i++ {
+		u := &unstructured.Unstructured{Object: testPod()}
+		u.SetName(fmt.Sprintf("pod-%d", i))
+		u.SetUID(types.UID(fmt.Sprintf("pod-%d", i)))
+		objs = append(objs, u)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, u := range objs {
+			table.AddOrUpdate(u)
+		}
+		table.Update(nil)
+	}
+}
@@ -0,0 +1,130 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+)
+
+// exprCostLimit bounds how much work evaluating an expression
+// can do so that a costly expression can't freeze the table
+const exprCostLimit = 1000000
+
+// celEnv returns the environment expressions are compiled in.
+// The object is available as self, like in the validation
+// rules of CustomResourceDefinitions.
+var celEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("self", cel.DynType),
+		ext.Strings(),
+		ext.Math(),
+		ext.Sets(),
+		ext.Encoders(),
+	)
+})
+
+// formatTypes are the types of values each format can
+// render. Columns without a format render any value.
+var formatTypes = map[string][]*cel.Type{
+	FormatAge:       {cel.StringType},
+	FormatTimestamp: {cel.StringType},
+	FormatQuantity:  {cel.StringType, cel.IntType, cel.DoubleType},
+	FormatBytes:     {cel.StringType, cel.IntType, cel.DoubleType},
+	FormatJoin:      {cel.ListType(cel.DynType)},
+	FormatKeyValue:  {cel.MapType(cel.DynType, cel.DynType)},
+	FormatCount:     {cel.ListType(cel.DynType), cel.MapType(cel.DynType, cel.DynType)},
+	FormatBool:      {cel.BoolType, cel.StringType},
+}
+
+// checkOutputType returns an error if an expression
+// returning values of type out can't be rendered
+// by format. Since the fields of self aren't typed,
+// expressions returning them are dyn and always pass.
+func checkOutputType(out *cel.Type, format string) error {
+	name, _, _ := strings.Cut(format, ":")
+	expected, ok := formatTypes[name]
+	if !ok {
+		return nil
+	}
+	// null is rendered as a missing value by every format
+	for _, t := range append(expected, cel.NullType) {
+		// dyn is assignable to and from any type
+		if t.IsAssignableType(out) || out.IsAssignableType(t) {
+			return nil
+		}
+	}
+	names := []string{}
+	for _, t := range expected {
+		names = append(names, t.String())
+	}
+	return fmt.Errorf("expression returns %s but format %q expects %s", out, name, strings.Join(names, " or "))
+}
+
+// exprExtractor computes the value of a
+// column by evaluating a CEL expression
+type exprExtractor struct {
+	program cel.Program
+}
+
+// compileExpr parses and type checks expr, checking
+// that its values can be rendered by the column format
+func compileExpr(expr, format string) (columnExtractor, error) {
+	env, err := celEnv()
+	if err != nil {
+		return nil, fmt.Errorf("creating expression environment: %w", err)
+	}
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression: %w", issues.Err())
+	}
+	if err := checkOutputType(ast.OutputType(), format); err != nil {
+		return nil, err
+	}
+	program, err := env.Program(ast, cel.CostLimit(exprCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return &exprExtractor{program: program}, nil
+}
+
+// extract evaluates the expression. Failing to evaluate
+// the expression for an object, i.e because a field it
+// uses is missing, only fails the cell of that object.
+func (e *exprExtractor) extract(obj *object) (interface{}, error) {
+	out, _, err := e.program.Eval(map[string]interface{}{"self": obj.content})
+	if err != nil {
+		return cellError{err: err}, nil
+	}
+	return nativeValue(out), nil
+}
+
+// nativeValue converts the result of an expression to the
+// values used for the contents of objects, so that they
+// are formatted and sorted like values read from a path
+func nativeValue(val ref.Val) interface{} {
+	switch v := val.(type) {
+	case types.Null:
+		return missingValue
+	case traits.Lister:
+		items := []interface{}{}
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			items = append(items, nativeValue(it.Next()))
+		}
+		return items
+	case traits.Mapper:
+		entries := map[string]interface{}{}
+		for it := v.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			entries[fmt.Sprint(key.Value())] = nativeValue(v.Get(key))
+		}
+		return entries
+	default:
+		return val.Value()
+	}
+}
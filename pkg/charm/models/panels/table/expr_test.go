@@ -0,0 +1,109 @@
+package table
+
+import (
+	"testing"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDeployment() map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "foo"},
+		"spec":     map[string]interface{}{"replicas": int64(4)},
+		"status": map[string]interface{}{
+			"readyReplicas": int64(3),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+			},
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "app", "restartCount": int64(2)},
+				map[string]interface{}{"name": "sidecar", "restartCount": int64(5)},
+			},
+		},
+	}
+}
+
+func TestCompileExpr(t *testing.T) {
+	for _, tc := range []struct {
+		expr     string
+		expected interface{}
+	}{
+		{expr: "self.metadata.name", expected: "foo"},
+		{expr: "double(self.status.readyReplicas) / double(self.spec.replicas)", expected: 0.75},
+		{expr: `string(self.status.readyReplicas) + "/" + string(self.spec.replicas)`, expected: "3/4"},
+		{expr: "math.greatest(self.status.containerStatuses.map(c, c.restartCount))", expected: int64(5)},
+		{expr: `self.status.conditions.exists(c, c.type == "Available" && c.status == "True") && self.status.containerStatuses.all(c, c.restartCount < 3)`, expected: false},
+		{expr: "self.status.containerStatuses.map(c, c.name)", expected: []interface{}{"app", "sidecar"}},
+		{expr: "has(self.spec.paused) ? self.spec.paused : null", expected: missingValue},
+	} {
+		t.Log(tc.expr)
+		extractor, err := compileExpr(tc.expr, "")
+		assert.NoError(t, err)
+		val, err := extractor.extract(newObject(testDeployment()))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, val)
+	}
+}
+
+func TestCompileExprInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"self.metadata.name +",
+		"other.metadata.name",
+		"unknownFunction(self)",
+	} {
+		t.Log(expr)
+		_, err := compileExpr(expr, "")
+		assert.Error(t, err)
+	}
+
+	t.Log("only one of path or expr can be set")
+	assert.Error(t, ValidateColumns([]buoytypes.Column{{Header: "Name", Path: "metadata.name", Expr: "self.metadata.name"}}))
+}
+
+func TestExprErrorsOnlyFailTheCell(t *testing.T) {
+	extractor, err := compileExpr("self.status.availableReplicas", "")
+	assert.NoError(t, err)
+	val, err := extractor.extract(newObject(testDeployment()))
+	assert.NoError(t, err)
+	assert.IsType(t, cellError{}, val)
+	assert.Contains(t, val.(cellError).String(), "no such key: availableReplicas")
+}
+
+func TestCompileExprOutputType(t *testing.T) {
+	for _, tc := range []struct {
+		expr   string
+		format string
+		valid  bool
+	}{
+		{expr: "self.metadata.creationTimestamp", format: "age", valid: true},
+		{expr: "size(self.status.conditions)", format: "age", valid: false},
+		{expr: "size(self.status.conditions)", format: "bytes", valid: true},
+		{expr: `"1Gi"`, format: "quantity", valid: true},
+		{expr: "[1, 2]", format: "quantity", valid: false},
+		{expr: `self.status.containerStatuses.map(c, c.name)`, format: "join", valid: true},
+		{expr: `["a", "b"]`, format: "join:/", valid: true},
+		{expr: `{"a": 1}`, format: "join", valid: false},
+		{expr: `{"a": 1}`, format: "keyvalue", valid: true},
+		{expr: `{"a": 1}`, format: "count", valid: true},
+		{expr: `"a"`, format: "count", valid: false},
+		{expr: "self.spec.replicas > 3", format: "bool", valid: true},
+		{expr: "has(self.spec.paused) ? self.spec.paused : null", format: "bool", valid: true},
+		{expr: "null", format: "bool", valid: true},
+		{expr: "1", format: "bool:yes/no", valid: false},
+		{expr: "1", format: "truncate:3", valid: true},
+		{expr: "[1]", format: "", valid: true},
+	} {
+		t.Logf("%s with format %q", tc.expr, tc.format)
+		_, err := compileExpr(tc.expr, tc.format)
+		if tc.valid {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	t.Log("the error names the expected types")
+	_, err := compileExpr("1", "bool")
+	assert.EqualError(t, err, `expression returns int but format "bool" expects bool or string`)
+}
@@ -0,0 +1,73 @@
+package table
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+	"github.com/sahilm/fuzzy"
+)
+
+// rowFilter decides which rows are shown while a filter is active
+type rowFilter struct {
+	// column is the header of the column to match,
+	// or empty to match the values of every column
+	column string
+	term   string
+	regex  *regexp.Regexp
+	err    error
+}
+
+// newRowFilter parses a filter term. A term of the form
+// column:term only matches the values of that column,
+// where column is a header of the table ignoring case.
+// The term is a regular expression if regex is true
+// and is fuzzy matched otherwise.
+func newRowFilter(term string, regex bool, columns []buoytypes.Column) *rowFilter {
+	f := &rowFilter{term: term}
+	if before, after, ok := strings.Cut(term, ":"); ok {
+		for _, column := range columns {
+			if strings.EqualFold(column.Header, before) {
+				f.column = column.Header
+				f.term = after
+				break
+			}
+		}
+	}
+	if regex {
+		f.regex, f.err = regexp.Compile(f.term)
+		if f.err != nil {
+			f.err = fmt.Errorf("invalid regex: %w", f.err)
+		}
+	}
+	return f
+}
+
+// matches returns whether or not the row should be shown.
+// Every row is shown if the filter is empty or invalid.
+func (f *rowFilter) matches(row tbl.Row) bool {
+	if f.term == "" || f.err != nil {
+		return true
+	}
+	for header, value := range row.Data {
+		if f.column != "" && header != f.column {
+			continue
+		}
+		if cell, ok := value.(tbl.StyledCell); ok {
+			value = cell.Data
+		}
+		if f.matchesValue(fmt.Sprint(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *rowFilter) matchesValue(value string) bool {
+	if f.regex != nil {
+		return f.regex.MatchString(value)
+	}
+	return len(fuzzy.Find(f.term, []string{value})) > 0
+}
@@ -0,0 +1,258 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Column formats, set as the format of a column
+// with the argument after a colon, i.e join:, or truncate:20
+const (
+	FormatAge       = "age"
+	FormatTimestamp = "timestamp"
+	FormatQuantity  = "quantity"
+	FormatBytes     = "bytes"
+	FormatJoin      = "join"
+	FormatKeyValue  = "keyvalue"
+	FormatCount     = "count"
+	FormatBool      = "bool"
+	FormatTruncate  = "truncate"
+)
+
+// formatFunc renders the value extracted for a column. The
+// current time is provided so that ages can be kept up to date.
+// Values that can't be formatted are returned as they are.
+type formatFunc func(value interface{}, now time.Time) interface{}
+
+// compileFormats returns the formatFunc of every column,
+// or nil for columns that render their values as they are
+func compileFormats(columns []buoytypes.Column) ([]formatFunc, error) {
+	formats := []formatFunc{}
+	errs := []error{}
+	for _, column := range columns {
+		format, err := compileFormat(column.Format)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("column %q: %w", column.Header, err))
+		}
+		formats = append(formats, format)
+	}
+	return formats, errors.Join(errs...)
+}
+
+func compileFormat(format string) (formatFunc, error) {
+	if format == "" {
+		return nil, nil
+	}
+	name, arg, hasArg := strings.Cut(format, ":")
+	switch name {
+	case FormatAge:
+		return formatAge, nil
+	case FormatTimestamp:
+		switch arg {
+		case "", "utc":
+			return formatTimestamp(time.UTC), nil
+		case "local":
+			return formatTimestamp(time.Local), nil
+		default:
+			return nil, fmt.Errorf("unknown timestamp zone %q, must be %q or %q", arg, "utc", "local")
+		}
+	case FormatQuantity:
+		return formatQuantity, nil
+	case FormatBytes:
+		return formatBytes, nil
+	case FormatJoin:
+		if !hasArg {
+			arg = ", "
+		}
+		return formatJoin(arg), nil
+	case FormatKeyValue:
+		if !hasArg {
+			arg = ", "
+		}
+		return formatKeyValue(arg), nil
+	case FormatCount:
+		return formatCount, nil
+	case FormatBool:
+		if !hasArg {
+			arg = "✓/✗"
+		}
+		yes, no, ok := strings.Cut(arg, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid bool format %q, must be of the form bool:true/false", format)
+		}
+		return formatBool(yes, no), nil
+	case FormatTruncate:
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid truncate format %q, must be of the form truncate:N where N is more than 0", format)
+		}
+		return formatTruncate(n), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// parseTime parses RFC3339 timestamps, the
+// format Kubernetes serializes times in
+func parseTime(value interface{}) (time.Time, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
+
+// parseQuantity parses quantities, i.e 500m or 2Gi, and numbers
+func parseQuantity(value interface{}) (resource.Quantity, bool) {
+	switch v := value.(type) {
+	case string:
+		q, err := resource.ParseQuantity(strings.TrimSpace(v))
+		return q, err == nil
+	case float64:
+		q, err := resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+		return q, err == nil
+	case int64:
+		return *resource.NewQuantity(v, resource.DecimalSI), true
+	case int:
+		return *resource.NewQuantity(int64(v), resource.DecimalSI), true
+	default:
+		return resource.Quantity{}, false
+	}
+}
+
+// formatAge renders how long ago a timestamp was, i.e 5m or 3d4h
+func formatAge(value interface{}, now time.Time) interface{} {
+	t, ok := parseTime(value)
+	if !ok {
+		return value
+	}
+	return duration.HumanDuration(now.Sub(t))
+}
+
+func formatTimestamp(loc *time.Location) formatFunc {
+	return func(value interface{}, _ time.Time) interface{} {
+		t, ok := parseTime(value)
+		if !ok {
+			return value
+		}
+		return t.In(loc).Format("2006-01-02 15:04:05 MST")
+	}
+}
+
+// formatQuantity renders quantities in their canonical form, i.e 1000m as 1
+func formatQuantity(value interface{}, _ time.Time) interface{} {
+	q, ok := parseQuantity(value)
+	if !ok {
+		return value
+	}
+	return q.String()
+}
+
+// formatBytes renders a number of bytes in binary units, i.e 1.5GiB
+func formatBytes(value interface{}, _ time.Time) interface{} {
+	q, ok := parseQuantity(value)
+	if !ok {
+		return value
+	}
+	bytes := float64(q.Value())
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	return strings.TrimSuffix(strconv.FormatFloat(bytes, 'f', 1, 64), ".0") + units[unit]
+}
+
+// formatJoin renders the items of a list separated by sep
+func formatJoin(sep string) formatFunc {
+	return func(value interface{}, _ time.Time) interface{} {
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		items := []string{}
+		for _, item := range list {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, sep)
+	}
+}
+
+// formatKeyValue renders the entries of a map as key=value,
+// ordered by key and separated by sep
+func formatKeyValue(sep string) formatFunc {
+	return func(value interface{}, _ time.Time) interface{} {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		entries := []string{}
+		for _, k := range keys {
+			entries = append(entries, fmt.Sprintf("%s=%v", k, m[k]))
+		}
+		return strings.Join(entries, sep)
+	}
+}
+
+// formatCount renders the number of items in a list or map
+func formatCount(value interface{}, _ time.Time) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return len(v)
+	case map[string]interface{}:
+		return len(v)
+	default:
+		return value
+	}
+}
+
+// formatBool renders booleans, including the "True" and
+// "False" strings used by conditions, as yes or no
+func formatBool(yes, no string) formatFunc {
+	return func(value interface{}, _ time.Time) interface{} {
+		var b bool
+		switch v := value.(type) {
+		case bool:
+			b = v
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return value
+			}
+			b = parsed
+		default:
+			return value
+		}
+		if b {
+			return yes
+		}
+		return no
+	}
+}
+
+// formatTruncate renders at most n characters of a value,
+// marking values that were cut short with an ellipsis
+func formatTruncate(n int) formatFunc {
+	return func(value interface{}, _ time.Time) interface{} {
+		runes := []rune(fmt.Sprint(value))
+		if len(runes) <= n {
+			return value
+		}
+		return string(runes[:n]) + "…"
+	}
+}
@@ -0,0 +1,80 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestFormats(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		format   string
+		value    interface{}
+		expected interface{}
+	}{
+		{format: "age", value: "2024-01-10T11:55:00Z", expected: "5m"},
+		{format: "age", value: "2024-01-07T10:00:00Z", expected: "3d2h"},
+		{format: "age", value: missingValue, expected: missingValue},
+		{format: "timestamp", value: "2024-01-10T11:55:00+02:00", expected: "2024-01-10 09:55:00 UTC"},
+		{format: "quantity", value: "1000m", expected: "1"},
+		{format: "quantity", value: float64(1.5), expected: "1500m"},
+		{format: "bytes", value: "1536Mi", expected: "1.5GiB"},
+		{format: "bytes", value: float64(512), expected: "512B"},
+		{format: "bytes", value: "2Ki", expected: "2KiB"},
+		{format: "join", value: []interface{}{"a", "b"}, expected: "a, b"},
+		{format: "join:|", value: []interface{}{"a", "b"}, expected: "a|b"},
+		{format: "keyvalue", value: map[string]interface{}{"b": "2", "a": "1"}, expected: "a=1, b=2"},
+		{format: "count", value: []interface{}{"a", "b", "c"}, expected: 3},
+		{format: "count", value: map[string]interface{}{"a": "1"}, expected: 1},
+		{format: "bool", value: true, expected: "✓"},
+		{format: "bool:yes/no", value: "False", expected: "no"},
+		{format: "bool:yes/no", value: "Unknown", expected: "Unknown"},
+		{format: "truncate:3", value: "abcdef", expected: "abc…"},
+		{format: "truncate:3", value: "abc", expected: "abc"},
+	} {
+		t.Logf("%s formats %v", tc.format, tc.value)
+		format, err := compileFormat(tc.format)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, format(tc.value, now))
+	}
+}
+
+func TestFormatsInvalid(t *testing.T) {
+	for _, format := range []string{"ages", "timestamp:mars", "bool:yes", "truncate:abc", "truncate:0"} {
+		t.Logf("%s is invalid", format)
+		_, err := compileFormat(format)
+		assert.Error(t, err)
+	}
+	err := ValidateColumns([]buoytypes.Column{{Header: "Age", Path: "metadata.creationTimestamp", Format: "ages"}})
+	assert.ErrorContains(t, err, `column "Age": unknown format "ages"`)
+}
+
+func TestTableAges(t *testing.T) {
+	columns := []buoytypes.Column{{Header: "Age", Path: "metadata.creationTimestamp", Format: "age"}}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "Age"}}}, Styles{})
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, name := range []string{"old", "new"} {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetUID(types.UID(name))
+		u.SetCreationTimestamp(metav1.NewTime(created.Add(time.Duration(i) * time.Minute)))
+		table.AddOrUpdate(u)
+	}
+	table.Update(nil)
+
+	t.Log("ages are ordered youngest first")
+	assert.Equal(t, "new", table.FetchRowForIndex(0).Identifier.Name)
+	assert.Equal(t, "59m", table.FetchRowForIndex(0).Row.Data["Age"])
+
+	t.Log("ages count up as time passes")
+	table.Update(created.Add(2 * time.Hour))
+	table.Update(nil)
+	assert.Equal(t, "119m", table.FetchRowForIndex(0).Row.Data["Age"])
+	assert.Equal(t, "120m", table.FetchRowForIndex(1).Row.Data["Age"])
+}
@@ -0,0 +1,114 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// jsonPathPrefix marks column paths that are kubectl
+// style JSONPath expressions instead of gjson paths
+const jsonPathPrefix = "jsonpath:"
+
+// relaxedJSONPathRegex matches the single field expressions
+// accepted by kubectl's custom-columns, i.e .metadata.name,
+// metadata.name or {.metadata.name}
+var relaxedJSONPathRegex = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
+
+// jsonPathExtractor resolves JSONPath expressions
+// the same way kubectl's -o jsonpath does
+type jsonPathExtractor struct {
+	jsonPath *jsonpath.JSONPath
+	// template is true for expressions that are rendered
+	// as text, i.e {.metadata.name}/{.metadata.namespace}
+	template bool
+}
+
+// compileJSONPath compiles a JSONPath expression. Single field
+// expressions keep the type of the value they resolve to while
+// templates of several expressions are rendered as text.
+func compileJSONPath(expr string) (columnExtractor, error) {
+	template := true
+	if submatches := relaxedJSONPathRegex.FindStringSubmatch(expr); submatches != nil {
+		field := submatches[1]
+		if field == "" {
+			field = submatches[2]
+		}
+		expr = fmt.Sprintf("{.%s}", field)
+		template = false
+	}
+	j := jsonpath.New("column").AllowMissingKeys(true)
+	if err := j.Parse(expr); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %q: %w", expr, err)
+	}
+	return &jsonPathExtractor{jsonPath: j, template: template}, nil
+}
+
+func (j *jsonPathExtractor) extract(obj *object) (interface{}, error) {
+	if j.template {
+		var out bytes.Buffer
+		if err := j.jsonPath.Execute(&out, obj.content); err != nil {
+			return cellError{err: err}, nil
+		}
+		if out.Len() == 0 {
+			return missingValue, nil
+		}
+		return out.String(), nil
+	}
+
+	results, err := j.jsonPath.FindResults(obj.content)
+	if err != nil {
+		return cellError{err: err}, nil
+	}
+	values := []interface{}{}
+	for _, result := range results {
+		for _, value := range result {
+			values = append(values, value.Interface())
+		}
+	}
+	switch len(values) {
+	case 0:
+		return missingValue, nil
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
+// ExpandCustomColumns adds the columns of a kubectl style
+// custom-columns spec, i.e NAME:.metadata.name,READY:.status.readyReplicas,
+// before any other columns of the table
+func ExpandCustomColumns(table *buoytypes.Table) error {
+	if table.CustomColumns == "" {
+		return nil
+	}
+	columns := []buoytypes.Column{}
+	for _, spec := range strings.Split(table.CustomColumns, ",") {
+		header, path, ok := strings.Cut(spec, ":")
+		if !ok || header == "" || path == "" {
+			return fmt.Errorf("unexpected custom-columns spec %q, expected <header>:<json-path-expr>", spec)
+		}
+		columns = append(columns, buoytypes.Column{Header: header, Path: jsonPathPrefix + path})
+	}
+	table.Columns = append(columns, table.Columns...)
+	table.CustomColumns = ""
+	return nil
+}
+
+// jsonPathMetadataRegex matches JSONPath expressions
+// that only read a field from the object metadata
+var jsonPathMetadataRegex = regexp.MustCompile(`^\{?\.?metadata(\.[A-Za-z0-9_\-.]*)?\}?$`)
+
+// readsMetadataOnly returns whether or not a
+// column path only reads the object metadata
+func readsMetadataOnly(path string) bool {
+	if expr, ok := strings.CutPrefix(path, jsonPathPrefix); ok {
+		return jsonPathMetadataRegex.MatchString(expr)
+	}
+	return path == "metadata" || strings.HasPrefix(path, "metadata.")
+}
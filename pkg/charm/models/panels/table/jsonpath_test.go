@@ -0,0 +1,53 @@
+package table
+
+import (
+	"testing"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileJSONPath(t *testing.T) {
+	for _, tc := range []struct {
+		path     string
+		expected interface{}
+	}{
+		{path: "jsonpath:.metadata.name", expected: "foo"},
+		{path: "jsonpath:metadata.name", expected: "foo"},
+		{path: "jsonpath:{.metadata.name}", expected: "foo"},
+		{path: "jsonpath:{.metadata.labels}", expected: map[string]interface{}{"app": "foo"}},
+		{path: "jsonpath:.spec.containers[*].name", expected: []interface{}{"app", "sidecar"}},
+		{path: "jsonpath:.spec.containers[1].image", expected: "bar:latest"},
+		{path: `jsonpath:.status.conditions[?(@.type=="Ready")].status`, expected: "True"},
+		{path: "jsonpath:.status.missing", expected: missingValue},
+		{path: "jsonpath:{.metadata.namespace}/{.metadata.name}", expected: "default/foo"},
+	} {
+		t.Log(tc.path)
+		extractor, err := compilePath(tc.path)
+		assert.NoError(t, err)
+		assert.IsType(t, &jsonPathExtractor{}, extractor)
+		val, err := extractor.extract(newObject(testPod()))
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, val)
+	}
+
+	t.Log("invalid expressions are errors")
+	_, err := compilePath("jsonpath:{.spec.containers[}")
+	assert.Error(t, err)
+}
+
+func TestExpandCustomColumns(t *testing.T) {
+	table := &buoytypes.Table{
+		CustomColumns: "NAME:.metadata.name,READY:.status.readyReplicas",
+		Columns:       []buoytypes.Column{{Header: "Age", Path: "metadata.creationTimestamp", Format: "age"}},
+	}
+	assert.NoError(t, ExpandCustomColumns(table))
+	assert.Equal(t, []buoytypes.Column{
+		{Header: "NAME", Path: "jsonpath:.metadata.name"},
+		{Header: "READY", Path: "jsonpath:.status.readyReplicas"},
+		{Header: "Age", Path: "metadata.creationTimestamp", Format: "age"},
+	}, table.Columns)
+
+	t.Log("every column needs a header and an expression")
+	assert.Error(t, ExpandCustomColumns(&buoytypes.Table{CustomColumns: "NAME:.metadata.name,READY"}))
+}
@@ -0,0 +1,90 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+)
+
+const (
+	// defaultMaxAutoWidth caps the width of auto
+	// sized columns that don't set a max width
+	defaultMaxAutoWidth = 40
+	// defaultFreezeColumns is the number of columns that stay
+	// in view while scrolling, i.e to keep the names in view
+	defaultFreezeColumns = 1
+)
+
+// ValidateLayout returns an error if the widths of
+// the columns or the number of frozen columns are invalid
+func ValidateLayout(table *buoytypes.Table) error {
+	errs := []error{}
+	for _, column := range table.Columns {
+		if column.Width < 0 && column.Width != buoytypes.ColumnWidthAuto {
+			errs = append(errs, fmt.Errorf("column %q: width must be a number more than 0 or auto", column.Header))
+		}
+		if column.MaxWidth < 0 {
+			errs = append(errs, fmt.Errorf("column %q: maxWidth must be more than 0", column.Header))
+		}
+	}
+	if freeze := table.FreezeColumns; freeze != nil && (*freeze < 0 || *freeze > len(table.Columns)) {
+		errs = append(errs, fmt.Errorf("freezeColumns must be between 0 and the number of columns, %d", len(table.Columns)))
+	}
+	return errors.Join(errs...)
+}
+
+// freezeColumns returns the number of columns
+// that stay in view while scrolling horizontally
+func freezeColumns(table *buoytypes.Table) int {
+	if table.FreezeColumns == nil {
+		return defaultFreezeColumns
+	}
+	return *table.FreezeColumns
+}
+
+// cellWidths returns the width of the value of every auto sized
+// column of the row, measured once when the row is formatted so
+// that the widths of the columns can be kept up to date cheaply.
+// The widths of other columns are 0.
+func cellWidths(columns []buoytypes.Column, row tbl.Row) []int {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		if column.Width != buoytypes.ColumnWidthAuto {
+			continue
+		}
+		value := row.Data[column.Header]
+		if cell, ok := value.(tbl.StyledCell); ok {
+			value = cell.Data
+		}
+		widths[i] = lipgloss.Width(fmt.Sprint(value))
+	}
+	return widths
+}
+
+// autoWidths returns the width that fits the header and the cells
+// of every auto sized column, up to its max width, from the cell
+// widths of each row. The widths of other columns are 0.
+func autoWidths(columns []buoytypes.Column, sortBy []buoytypes.SortKey, rows [][]int) []int {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		if column.Width != buoytypes.ColumnWidthAuto {
+			continue
+		}
+		maxWidth := column.MaxWidth
+		if maxWidth <= 0 {
+			maxWidth = defaultMaxAutoWidth
+		}
+		width := lipgloss.Width(column.Header + sortIndicator(column.Header, sortBy))
+		for _, row := range rows {
+			width = max(width, row[i])
+			if width >= maxWidth {
+				break
+			}
+		}
+		widths[i] = min(width, maxWidth)
+	}
+	return widths
+}
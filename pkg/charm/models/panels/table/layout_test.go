@@ -0,0 +1,62 @@
+package table
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnWidthJSON(t *testing.T) {
+	table := &buoytypes.Table{}
+	err := json.Unmarshal([]byte(`{"columns": [{"header": "Name", "width": "auto"}, {"header": "Phase", "width": 10}, {"header": "Node"}]}`), table)
+	assert.NoError(t, err)
+	assert.Equal(t, buoytypes.ColumnWidthAuto, table.Columns[0].Width)
+	assert.Equal(t, buoytypes.ColumnWidth(10), table.Columns[1].Width)
+	assert.Equal(t, buoytypes.ColumnWidth(0), table.Columns[2].Width)
+
+	t.Log("widths that aren't numbers or auto are errors")
+	assert.Error(t, json.Unmarshal([]byte(`{"columns": [{"header": "Name", "width": "wide"}]}`), table))
+}
+
+func TestAutoWidths(t *testing.T) {
+	columns := []buoytypes.Column{
+		{Header: "Name", Width: buoytypes.ColumnWidthAuto},
+		{Header: "Phase", Width: 10},
+		{Header: "Message", Width: buoytypes.ColumnWidthAuto, MaxWidth: 12},
+		{Header: "Labels", Width: buoytypes.ColumnWidthAuto},
+	}
+	rows := [][]int{
+		cellWidths(columns, tbl.NewRow(tbl.RowData{"Name": "foo", "Phase": "Running", "Message": "all good", "Labels": "a=b"})),
+		cellWidths(columns, tbl.NewRow(tbl.RowData{"Name": "foobarbaz", "Phase": "Pending", "Message": "waiting for the node to be ready", "Labels": "app=web\ntier=frontend"})),
+	}
+
+	t.Log("only the values of auto columns are measured")
+	assert.Equal(t, []int{3, 0, 8, 3}, rows[0])
+
+	t.Log("auto columns fit their widest value, up to their max width")
+	assert.Equal(t, []int{9, 0, 12, 13}, autoWidths(columns, nil, rows))
+
+	t.Log("auto columns fit their header and sort indicator")
+	assert.Equal(t, []int{4, 0, 8, 6}, autoWidths(columns, nil, rows[:1]))
+	assert.Equal(t, []int{6, 0, 8, 6}, autoWidths(columns, []buoytypes.SortKey{{Column: "Name"}}, rows[:1]))
+
+	t.Log("the default max width caps columns without one")
+	long := cellWidths(columns, tbl.NewRow(tbl.RowData{"Name": strings.Repeat("x", 100)}))
+	assert.Equal(t, defaultMaxAutoWidth, autoWidths(columns, nil, [][]int{long})[0])
+}
+
+func TestValidateLayout(t *testing.T) {
+	freeze := func(n int) *int { return &n }
+	columns := []buoytypes.Column{{Header: "Name"}, {Header: "Phase"}}
+
+	assert.NoError(t, ValidateLayout(&buoytypes.Table{Columns: columns}))
+	assert.NoError(t, ValidateLayout(&buoytypes.Table{Columns: columns, FreezeColumns: freeze(0)}))
+	assert.NoError(t, ValidateLayout(&buoytypes.Table{Columns: columns, FreezeColumns: freeze(2)}))
+	assert.Error(t, ValidateLayout(&buoytypes.Table{Columns: columns, FreezeColumns: freeze(3)}))
+	assert.Error(t, ValidateLayout(&buoytypes.Table{Columns: []buoytypes.Column{{Header: "Name", Width: -5}}}))
+	assert.Error(t, ValidateLayout(&buoytypes.Table{Columns: []buoytypes.Column{{Header: "Name", MaxWidth: -1}}}))
+}
@@ -0,0 +1,58 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ValidateSelectors returns an error if the label or field
+// selector of the table are invalid or if the table sets
+// both a namespace and a list of namespaces
+func ValidateSelectors(table *buoytypes.Table) error {
+	_, _, err := compileSelectors(table)
+	if table.Namespace != "" && len(table.Namespaces) > 0 {
+		err = errors.Join(err, errors.New("only one of namespace or namespaces can be set"))
+	}
+	return err
+}
+
+// compileSelectors returns the label and field selectors of the
+// table. Invalid selectors are returned as selecting everything.
+func compileSelectors(table *buoytypes.Table) (labels.Selector, fields.Selector, error) {
+	errs := []error{}
+	labelSelector, err := labelSelectorFor(table.LabelSelector)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid label selector: %w", err))
+		labelSelector = labels.Everything()
+	}
+	fieldSelector, err := fields.ParseSelector(table.FieldSelector)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid field selector: %w", err))
+		fieldSelector = fields.Everything()
+	}
+	return labelSelector, fieldSelector, errors.Join(errs...)
+}
+
+func labelSelectorFor(selector buoytypes.LabelSelector) (labels.Selector, error) {
+	if selector.Expression != "" {
+		return labels.Parse(selector.Expression)
+	}
+	return metav1.LabelSelectorAsSelector(&selector.LabelSelector)
+}
+
+// namespacesFor returns the namespaces the table shows resources
+// of, where the empty namespace is every namespace
+func namespacesFor(table *buoytypes.Table) []string {
+	if len(table.Namespaces) == 0 {
+		return []string{table.Namespace}
+	}
+	namespaces := slices.Clone(table.Namespaces)
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
@@ -0,0 +1,72 @@
+package table
+
+import (
+	"encoding/json"
+	"testing"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestSelectors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		blob     string
+		matches  labels.Set
+		excludes labels.Set
+	}{
+		{
+			name:     "map of labels",
+			blob:     `{"labelSelector": {"app": "foo"}}`,
+			matches:  labels.Set{"app": "foo", "tier": "web"},
+			excludes: labels.Set{"app": "bar"},
+		},
+		{
+			name:     "string form",
+			blob:     `{"labelSelector": "app in (foo,bar),!legacy"}`,
+			matches:  labels.Set{"app": "bar"},
+			excludes: labels.Set{"app": "foo", "legacy": "true"},
+		},
+		{
+			name:     "match expressions",
+			blob:     `{"labelSelector": {"matchLabels": {"tier": "web"}, "matchExpressions": [{"key": "app", "operator": "NotIn", "values": ["bar"]}]}}`,
+			matches:  labels.Set{"app": "foo", "tier": "web"},
+			excludes: labels.Set{"app": "bar", "tier": "web"},
+		},
+		{
+			name:    "no selector",
+			blob:    `{}`,
+			matches: labels.Set{"app": "foo"},
+		},
+	} {
+		t.Log(tc.name)
+		table := &buoytypes.Table{}
+		assert.NoError(t, json.Unmarshal([]byte(tc.blob), table))
+		assert.NoError(t, ValidateSelectors(table))
+		labelSelector, _, err := compileSelectors(table)
+		assert.NoError(t, err)
+		assert.True(t, labelSelector.Matches(tc.matches))
+		if tc.excludes != nil {
+			assert.False(t, labelSelector.Matches(tc.excludes))
+		}
+	}
+
+	t.Log("field selectors are parsed")
+	_, fieldSelector, err := compileSelectors(&buoytypes.Table{FieldSelector: "status.phase!=Running,spec.nodeName=node1"})
+	assert.NoError(t, err)
+	assert.True(t, fieldSelector.Matches(fields.Set{"status.phase": "Pending", "spec.nodeName": "node1"}))
+	assert.False(t, fieldSelector.Matches(fields.Set{"status.phase": "Running", "spec.nodeName": "node1"}))
+
+	t.Log("invalid selectors are errors")
+	assert.Error(t, ValidateSelectors(&buoytypes.Table{LabelSelector: buoytypes.LabelSelector{Expression: "app in (foo"}}))
+	assert.Error(t, ValidateSelectors(&buoytypes.Table{FieldSelector: "status.phase"}))
+	assert.Error(t, ValidateSelectors(&buoytypes.Table{Namespace: "default", Namespaces: []string{"other"}}))
+}
+
+func TestNamespaces(t *testing.T) {
+	assert.Equal(t, []string{""}, New(DefaultKeys, &buoytypes.Table{}, Styles{}).Namespaces())
+	assert.Equal(t, []string{"default"}, New(DefaultKeys, &buoytypes.Table{Namespace: "default"}, Styles{}).Namespaces())
+	assert.Equal(t, []string{"a", "b"}, New(DefaultKeys, &buoytypes.Table{Namespaces: []string{"b", "a", "b"}}, Styles{}).Namespaces())
+}
@@ -0,0 +1,233 @@
+package table
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// sortKind is the kind of a value being sorted. Values
+// of different kinds are ordered by their kind, with
+// missing values always ordered last.
+type sortKind int
+
+const (
+	kindQuantity sortKind = iota
+	kindTime
+	kindString
+	kindMissing
+)
+
+// sortValue is a column value parsed so that it can be
+// compared by what it represents instead of its text,
+// i.e 500m is less than 2 and 1Gi is more than 900Mi
+type sortValue struct {
+	kind     sortKind
+	quantity resource.Quantity
+	time     time.Time
+	text     string
+	// reverse orders later times first, i.e
+	// for timestamps that are shown as ages
+	reverse bool
+}
+
+func newSortValue(value interface{}) sortValue {
+	if cell, ok := value.(tbl.StyledCell); ok {
+		value = cell.Data
+	}
+	switch value.(type) {
+	case nil, missing, cellError:
+		return sortValue{kind: kindMissing}
+	}
+	text := strings.TrimSpace(fmt.Sprint(value))
+	if text == "" {
+		return sortValue{kind: kindMissing}
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return sortValue{kind: kindTime, time: t}
+	}
+	if q, err := resource.ParseQuantity(text); err == nil {
+		return sortValue{kind: kindQuantity, quantity: q}
+	}
+	return sortValue{kind: kindString, text: text}
+}
+
+func (v sortValue) compare(other sortValue) int {
+	if v.kind != other.kind {
+		if v.kind < other.kind {
+			return -1
+		}
+		return 1
+	}
+	switch v.kind {
+	case kindQuantity:
+		return v.quantity.Cmp(other.quantity)
+	case kindTime:
+		if v.reverse {
+			return other.time.Compare(v.time)
+		}
+		return v.time.Compare(other.time)
+	case kindString:
+		return strings.Compare(v.text, other.text)
+	default:
+		return 0
+	}
+}
+
+// ValidateSort returns an error if the table
+// is sorted by a column that it doesn't have
+func ValidateSort(table *buoytypes.Table) error {
+	for _, key := range table.SortBy {
+		if columnIndex(table.Columns, key.Column) < 0 {
+			return fmt.Errorf("sorting by unknown column %q", key.Column)
+		}
+		switch key.Order {
+		case "", buoytypes.SortAscending, buoytypes.SortDescending:
+		default:
+			return fmt.Errorf("sorting by column %q: unknown order %q, must be %q or %q", key.Column, key.Order, buoytypes.SortAscending, buoytypes.SortDescending)
+		}
+	}
+	return nil
+}
+
+// normalizeSort returns the sort keys with the default order filled in
+func normalizeSort(sortBy []buoytypes.SortKey) []buoytypes.SortKey {
+	keys := []buoytypes.SortKey{}
+	for _, key := range sortBy {
+		if key.Order == "" {
+			key.Order = buoytypes.SortAscending
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func columnIndex(columns []buoytypes.Column, header string) int {
+	for i, column := range columns {
+		if column.Header == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortValues returns the values of a row for each sort key,
+// parsed once when the row is added so that comparing rows
+// is cheap. Ages are ordered youngest first.
+func sortValues(values tbl.RowData, sortBy []buoytypes.SortKey, columns []buoytypes.Column) []sortValue {
+	sortValues := make([]sortValue, 0, len(sortBy))
+	for _, key := range sortBy {
+		value := newSortValue(values[key.Column])
+		if i := columnIndex(columns, key.Column); i >= 0 && columns[i].Format == FormatAge {
+			value.reverse = true
+		}
+		sortValues = append(sortValues, value)
+	}
+	return sortValues
+}
+
+// compareRows orders rows by the sort keys their sort values were
+// parsed for. Rows that are equal for every key are ordered by
+// namespace and name so that rows don't move around between updates.
+func compareRows(a, b *RowInfo, sortBy []buoytypes.SortKey) int {
+	for k, key := range sortBy {
+		x, y := a.sortValues[k], b.sortValues[k]
+		cmp := x.compare(y)
+		// missing values stay last in either order
+		if key.Order == buoytypes.SortDescending && x.kind != kindMissing && y.kind != kindMissing {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	if cmp := strings.Compare(a.name, b.name); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(string(a.UID), string(b.UID))
+}
+
+// sortRows orders every row by the sort keys
+func sortRows(rows []*RowInfo, sortBy []buoytypes.SortKey) {
+	slices.SortFunc(rows, func(a, b *RowInfo) int {
+		return compareRows(a, b, sortBy)
+	})
+}
+
+// insertRow adds row to the sorted rows where it belongs
+func insertRow(rows []*RowInfo, row *RowInfo, sortBy []buoytypes.SortKey) []*RowInfo {
+	i, _ := slices.BinarySearchFunc(rows, row, func(a, b *RowInfo) int {
+		return compareRows(a, b, sortBy)
+	})
+	return slices.Insert(rows, i, row)
+}
+
+// removeRow removes row from the sorted rows
+func removeRow(rows []*RowInfo, row *RowInfo, sortBy []buoytypes.SortKey) []*RowInfo {
+	i, found := slices.BinarySearchFunc(rows, row, func(a, b *RowInfo) int {
+		return compareRows(a, b, sortBy)
+	})
+	if !found || rows[i] != row {
+		if i = slices.Index(rows, row); i < 0 {
+			return rows
+		}
+	}
+	return slices.Delete(rows, i, i+1)
+}
+
+// nextSort sorts by the column after the column currently
+// sorted by, in ascending order. The other sort keys are
+// kept to order rows that are equal for the column.
+func nextSort(columns []buoytypes.Column, sortBy []buoytypes.SortKey) []buoytypes.SortKey {
+	if len(columns) == 0 {
+		return sortBy
+	}
+	next := 0
+	if len(sortBy) > 0 {
+		next = (columnIndex(columns, sortBy[0].Column) + 1) % len(columns)
+	}
+	return withPrimarySort(sortBy, buoytypes.SortKey{Column: columns[next].Header, Order: buoytypes.SortAscending})
+}
+
+// reverseSort flips the order of the column currently sorted by
+func reverseSort(columns []buoytypes.Column, sortBy []buoytypes.SortKey) []buoytypes.SortKey {
+	if len(sortBy) == 0 {
+		if len(columns) == 0 {
+			return sortBy
+		}
+		return []buoytypes.SortKey{{Column: columns[0].Header, Order: buoytypes.SortDescending}}
+	}
+	primary := sortBy[0]
+	primary.Order = buoytypes.SortDescending
+	if sortBy[0].Order == buoytypes.SortDescending {
+		primary.Order = buoytypes.SortAscending
+	}
+	return withPrimarySort(sortBy, primary)
+}
+
+func withPrimarySort(sortBy []buoytypes.SortKey, primary buoytypes.SortKey) []buoytypes.SortKey {
+	keys := []buoytypes.SortKey{primary}
+	for _, key := range sortBy {
+		if key.Column != primary.Column {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// sortIndicator returns the marker shown in
+// the header of the column sorted by first
+func sortIndicator(header string, sortBy []buoytypes.SortKey) string {
+	if len(sortBy) == 0 || sortBy[0].Column != header {
+		return ""
+	}
+	if sortBy[0].Order == buoytypes.SortDescending {
+		return " ▼"
+	}
+	return " ▲"
+}
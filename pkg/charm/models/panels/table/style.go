@@ -0,0 +1,167 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// styleRule is a compiled buoytypes.StyleRule
+type styleRule struct {
+	equals      string
+	matches     *regexp.Regexp
+	lessThan    *resource.Quantity
+	greaterThan *resource.Quantity
+	style       lipgloss.Style
+}
+
+// rowStyleRule is a compiled buoytypes.RowStyleRule
+type rowStyleRule struct {
+	extractor columnExtractor
+	rule      *styleRule
+}
+
+// ValidateStyles returns an error if any of the style rules
+// of the table are invalid or use colors that aren't in palette
+func ValidateStyles(table *buoytypes.Table, palette map[string]lipgloss.AdaptiveColor) error {
+	_, errCells := compileCellStyles(table.Columns, palette)
+	_, errRows := compileRowStyles(table.RowStyles, palette)
+	return errors.Join(errCells, errRows)
+}
+
+// compileCellStyles returns the style rules of every column
+func compileCellStyles(columns []buoytypes.Column, palette map[string]lipgloss.AdaptiveColor) ([][]*styleRule, error) {
+	styles := [][]*styleRule{}
+	errs := []error{}
+	for _, column := range columns {
+		rules := []*styleRule{}
+		for i, rule := range column.Styles {
+			compiled, err := compileStyleRule(rule, palette)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("column %q: style %d: %w", column.Header, i, err))
+				continue
+			}
+			rules = append(rules, compiled)
+		}
+		styles = append(styles, rules)
+	}
+	return styles, errors.Join(errs...)
+}
+
+func compileRowStyles(rowStyles []buoytypes.RowStyleRule, palette map[string]lipgloss.AdaptiveColor) ([]*rowStyleRule, error) {
+	rules := []*rowStyleRule{}
+	errs := []error{}
+	for i, rowStyle := range rowStyles {
+		extractor, err := compilePath(rowStyle.Path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row style %d: %w", i, err))
+			continue
+		}
+		rule, err := compileStyleRule(rowStyle.StyleRule, palette)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row style %d: %w", i, err))
+			continue
+		}
+		rules = append(rules, &rowStyleRule{extractor: extractor, rule: rule})
+	}
+	return rules, errors.Join(errs...)
+}
+
+func compileStyleRule(rule buoytypes.StyleRule, palette map[string]lipgloss.AdaptiveColor) (*styleRule, error) {
+	if rule.Equals == "" && rule.Matches == "" && rule.LessThan == nil && rule.GreaterThan == nil {
+		return nil, errors.New("must have at least one of equals, matches, lessThan or greaterThan")
+	}
+	compiled := &styleRule{
+		equals:      rule.Equals,
+		lessThan:    rule.LessThan,
+		greaterThan: rule.GreaterThan,
+	}
+	if rule.Matches != "" {
+		matches, err := regexp.Compile(rule.Matches)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matches: %w", err)
+		}
+		compiled.matches = matches
+	}
+
+	style := lipgloss.NewStyle().Bold(rule.Bold).Italic(rule.Italic).Faint(rule.Faint)
+	if rule.Color != "" {
+		color, err := paletteColor(rule.Color, palette)
+		if err != nil {
+			return nil, fmt.Errorf("color: %w", err)
+		}
+		style = style.Foreground(color)
+	}
+	if rule.Background != "" {
+		color, err := paletteColor(rule.Background, palette)
+		if err != nil {
+			return nil, fmt.Errorf("background: %w", err)
+		}
+		style = style.Background(color)
+	}
+	compiled.style = style
+	return compiled, nil
+}
+
+func paletteColor(name string, palette map[string]lipgloss.AdaptiveColor) (lipgloss.AdaptiveColor, error) {
+	color, ok := palette[name]
+	if !ok {
+		names := []string{}
+		for n := range palette {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return color, fmt.Errorf("unknown color %q, must be one of the palette colors: %s", name, strings.Join(names, ", "))
+	}
+	return color, nil
+}
+
+// matchesValue returns whether or not the value meets every
+// condition of the rule. Missing values and errors never match.
+func (r *styleRule) matchesValue(value interface{}) bool {
+	if cell, ok := value.(tbl.StyledCell); ok {
+		value = cell.Data
+	}
+	switch value.(type) {
+	case missing, cellError:
+		return false
+	}
+	text := fmt.Sprint(value)
+	if r.equals != "" && text != r.equals {
+		return false
+	}
+	if r.matches != nil && !r.matches.MatchString(text) {
+		return false
+	}
+	if r.lessThan != nil || r.greaterThan != nil {
+		q, ok := parseQuantity(value)
+		if !ok {
+			return false
+		}
+		if r.lessThan != nil && q.Cmp(*r.lessThan) >= 0 {
+			return false
+		}
+		if r.greaterThan != nil && q.Cmp(*r.greaterThan) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// firstMatch returns the style of the first rule value
+// matches and whether or not any of the rules matched
+func firstMatch(rules []*styleRule, value interface{}) (lipgloss.Style, bool) {
+	for _, rule := range rules {
+		if rule.matchesValue(value) {
+			return rule.style, true
+		}
+	}
+	return lipgloss.Style{}, false
+}
@@ -0,0 +1,156 @@
+package table
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	tbl "github.com/evertras/bubble-table/table"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testPalette = map[string]lipgloss.AdaptiveColor{
+	"red":    {Light: "1", Dark: "1"},
+	"green":  {Light: "2", Dark: "2"},
+	"yellow": {Light: "3", Dark: "3"},
+}
+
+func TestStyleRules(t *testing.T) {
+	one := resource.MustParse("1")
+	for _, tc := range []struct {
+		name     string
+		rule     buoytypes.StyleRule
+		value    interface{}
+		expected bool
+	}{
+		{name: "equals", rule: buoytypes.StyleRule{Equals: "Running"}, value: "Running", expected: true},
+		{name: "not equals", rule: buoytypes.StyleRule{Equals: "Running"}, value: "Pending", expected: false},
+		{name: "matches", rule: buoytypes.StyleRule{Matches: "CrashLoop.*"}, value: "CrashLoopBackOff", expected: true},
+		{name: "less than a number", rule: buoytypes.StyleRule{LessThan: &one}, value: float64(0), expected: true},
+		{name: "less than a quantity", rule: buoytypes.StyleRule{LessThan: &one}, value: "500m", expected: true},
+		{name: "not less than", rule: buoytypes.StyleRule{LessThan: &one}, value: "2Gi", expected: false},
+		{name: "greater than", rule: buoytypes.StyleRule{GreaterThan: &one}, value: "2", expected: true},
+		{name: "not a number", rule: buoytypes.StyleRule{GreaterThan: &one}, value: "Running", expected: false},
+		{name: "every condition", rule: buoytypes.StyleRule{Matches: "^R", Equals: "Ready"}, value: "Running", expected: false},
+		{name: "missing value", rule: buoytypes.StyleRule{Matches: ".+"}, value: missingValue, expected: false},
+		{name: "missing value placeholder", rule: buoytypes.StyleRule{Equals: defaultPlaceholder}, value: missingValue, expected: false},
+		{name: "error", rule: buoytypes.StyleRule{Matches: ".+"}, value: cellError{err: errors.New("boom")}, expected: false},
+	} {
+		t.Log(tc.name)
+		rule, err := compileStyleRule(tc.rule, testPalette)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, rule.matchesValue(tc.value))
+	}
+}
+
+func TestValidateStyles(t *testing.T) {
+	t.Log("colors must be in the palette")
+	err := ValidateStyles(&buoytypes.Table{Columns: []buoytypes.Column{
+		{Header: "Phase", Path: "status.phase", Styles: []buoytypes.StyleRule{{Equals: "Running", Color: "purple"}}},
+	}}, testPalette)
+	assert.EqualError(t, err, `column "Phase": style 0: color: unknown color "purple", must be one of the palette colors: green, red, yellow`)
+
+	t.Log("rules must have a condition")
+	err = ValidateStyles(&buoytypes.Table{RowStyles: []buoytypes.RowStyleRule{
+		{Path: "status.phase", StyleRule: buoytypes.StyleRule{Color: "red"}},
+	}}, testPalette)
+	assert.ErrorContains(t, err, "row style 0: must have at least one of")
+
+	t.Log("regular expressions must be valid")
+	err = ValidateStyles(&buoytypes.Table{RowStyles: []buoytypes.RowStyleRule{
+		{Path: "status.phase", StyleRule: buoytypes.StyleRule{Matches: "(", Color: "red"}},
+	}}, testPalette)
+	assert.ErrorContains(t, err, "row style 0: invalid matches")
+}
+
+func TestTableStyles(t *testing.T) {
+	table := New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Path: "metadata.name"},
+			{Header: "Phase", Path: "status.phase", Styles: []buoytypes.StyleRule{
+				{Equals: "Running", Color: "green"},
+				{Matches: "CrashLoop.*", Color: "red", Bold: true},
+			}},
+		},
+		RowStyles: []buoytypes.RowStyleRule{
+			{Path: "metadata.labels.tier", StyleRule: buoytypes.StyleRule{Equals: "critical", Color: "yellow"}},
+			{Path: "metadata.deletionTimestamp", StyleRule: buoytypes.StyleRule{Matches: ".+", Faint: true}},
+		},
+	}, Styles{Palette: testPalette})
+	add := func(name, phase, tier string) *RowInfo {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"phase": phase}}}
+		u.SetName(name)
+		u.SetUID(types.UID(name))
+		u.SetLabels(map[string]string{"tier": tier})
+		table.AddOrUpdate(u)
+		return table.rows[types.UID(name)]
+	}
+
+	t.Log("the first rule a value matches styles the cell")
+	row := add("a", "Running", "")
+	cell, ok := row.Row.Data["Phase"].(tbl.StyledCell)
+	assert.True(t, ok)
+	assert.Equal(t, "Running", cell.Data)
+	assert.Equal(t, testPalette["green"], cell.Style.GetForeground())
+
+	row = add("b", "CrashLoopBackOff", "")
+	cell = row.Row.Data["Phase"].(tbl.StyledCell)
+	assert.Equal(t, testPalette["red"], cell.Style.GetForeground())
+	assert.True(t, cell.Style.GetBold())
+
+	t.Log("values that match no rules aren't styled")
+	row = add("c", "Pending", "")
+	assert.Equal(t, "Pending", row.Row.Data["Phase"])
+
+	t.Log("row rules style the whole row from any path")
+	row = add("d", "Pending", "critical")
+	assert.Equal(t, testPalette["yellow"], row.style.GetForeground())
+
+	t.Log("row rules don't match objects without their path")
+	assert.False(t, row.style.GetFaint())
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetName("e")
+	u.SetUID(types.UID("e"))
+	u.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+	table.AddOrUpdate(u)
+	assert.True(t, table.rows[types.UID("e")].style.GetFaint())
+}
+
+func TestTableStylesFormatted(t *testing.T) {
+	gi := resource.MustParse("1Gi")
+	table := New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Ready", Path: "status.ready", Format: "bool:yes/no", Styles: []buoytypes.StyleRule{
+				{Equals: "false", Color: "red"},
+			}},
+			{Header: "Memory", Path: "status.memory", Format: "bytes", Styles: []buoytypes.StyleRule{
+				{GreaterThan: &gi, Color: "yellow"},
+			}},
+		},
+	}, Styles{Palette: testPalette})
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{
+		"ready":  false,
+		"memory": "2147483648",
+	}}}
+	u.SetName("a")
+	u.SetUID(types.UID("a"))
+	table.AddOrUpdate(u)
+	row := table.rows[types.UID("a")]
+
+	t.Log("rules are checked against values before they are formatted")
+	cell, ok := row.Row.Data["Ready"].(tbl.StyledCell)
+	assert.True(t, ok)
+	assert.Equal(t, "no", cell.Data)
+	assert.Equal(t, testPalette["red"], cell.Style.GetForeground())
+
+	cell, ok = row.Row.Data["Memory"].(tbl.StyledCell)
+	assert.True(t, ok)
+	assert.Equal(t, "2GiB", cell.Data)
+	assert.Equal(t, testPalette["yellow"], cell.Style.GetForeground())
+}
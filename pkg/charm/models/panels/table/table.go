@@ -3,36 +3,55 @@ package table
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/alecthomas/chroma/quick"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 	buoytypes "github.com/everettraven/buoy/pkg/types"
 	tbl "github.com/evertras/bubble-table/table"
-	"github.com/tidwall/gjson"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
-	modeView  = "view"
-	modeTable = "table"
+	modeView    = "view"
+	modeTable   = "table"
+	modeInspect = "inspect"
 	// TODO: These default sizes should probably
 	// be configurable
 	defaultPageSize    = 5
 	defaultColumnWidth = 20
+	// tableFrameHeight is the number of lines used by the
+	// table borders, header and footer around the rows
+	tableFrameHeight = 6
 )
 
 type KeyMap struct {
 	ViewModeToggle key.Binding
+	SortNext       key.Binding
+	SortReverse    key.Binding
+	Filter         key.Binding
+	SubmitFilter   key.Binding
+	ClearFilter    key.Binding
+	ToggleRegex    key.Binding
+	InspectRow     key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	ToggleWrap     key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -47,6 +66,20 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{
 			k.ViewModeToggle,
+			k.InspectRow,
+			k.SortNext,
+			k.SortReverse,
+		},
+		{
+			k.ScrollLeft,
+			k.ScrollRight,
+			k.ToggleWrap,
+		},
+		{
+			k.Filter,
+			k.SubmitFilter,
+			k.ClearFilter,
+			k.ToggleRegex,
 		},
 	}
 }
@@ -56,12 +89,69 @@ var DefaultKeys = KeyMap{
 		key.WithKeys("v"),
 		key.WithHelp("v", "toggle viewing contents of selected resource"),
 	),
+	InspectRow: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "toggle inspecting the cells of selected resource"),
+	),
+	SortNext: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort by the next column"),
+	),
+	SortReverse: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "reverse the sort order"),
+	),
+	ScrollLeft: key.NewBinding(
+		key.WithKeys("shift+left", "<"),
+		key.WithHelp("shift+←/<", "scroll columns left"),
+	),
+	ScrollRight: key.NewBinding(
+		key.WithKeys("shift+right", ">"),
+		key.WithHelp("shift+→/>", "scroll columns right"),
+	),
+	ToggleWrap: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "toggle wrapping long values over several lines"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "open a prompt to filter rows"),
+	),
+	SubmitFilter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "stop typing the filter"),
+	),
+	ClearFilter: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear the filter"),
+	),
+	ToggleRegex: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "toggle regex filter mode"),
+	),
 }
 
 type RowInfo struct {
 	Row        tbl.Row
+	UID        types.UID
 	Identifier *types.NamespacedName
 	Index      int
+	// values are the values of each column before
+	// they are formatted, keyed by column header
+	values tbl.RowData
+	// style is the style of the row rule the row matched
+	style lipgloss.Style
+	// name is the namespace/name of the row, which
+	// orders rows that are equal for every sort key
+	name string
+	// sortValues are the values of the row for each sort key
+	sortValues []sortValue
+	// widths are the widths of the auto sized cells of the row
+	widths []int
+	// matched is whether or not the row matches the filter
+	matched bool
+	// errors is the number of cells that couldn't be computed
+	errors int
 }
 
 type Styles struct {
@@ -69,6 +159,13 @@ type Styles struct {
 	TextAlignment        lipgloss.Style
 	SyntaxHighlightDark  string
 	SyntaxHighlightLight string
+	FilterPrompt         string
+	FilterPlaceholder    string
+	FilterStyle          lipgloss.Style
+	// CellErrorStyle styles cells that couldn't be computed
+	CellErrorStyle lipgloss.Style
+	// Palette is the colors style rules can use by name
+	Palette map[string]lipgloss.AdaptiveColor
 }
 
 type ViewActionFunc func(row *RowInfo) (string, error)
@@ -76,32 +173,79 @@ type ViewActionFunc func(row *RowInfo) (string, error)
 // Model is a tea.Model implementation
 // that represents a table panel
 type Model struct {
+	helper.VisibilityHooks
+	helper.ActivityTracker
+	helper.ConnectionTracker
 	tableModel tbl.Model
 	viewport   viewport.Model
 	mode       string
 	mutex      *sync.Mutex
 	rows       map[types.UID]*RowInfo
+	// sorted are the rows in the order they are shown
+	sorted     []*RowInfo
 	columns    []buoytypes.Column
-	err        error
-	tempRows   []tbl.Row
+	extractors []columnExtractor
+	formats    []formatFunc
+	cellStyles [][]*styleRule
+	rowStyles  []*rowStyleRule
+	// labelSelector and fieldSelector select
+	// the resources shown in the table
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+	ticking       bool
+	lastTick      time.Time
+	err           error
+	// dirty is whether or not the rows changed since
+	// they were last shown, so that they are rebuilt
+	// once per render instead of once per event
+	dirty      bool
+	shown      []types.UID
 	keys       KeyMap
 	table      *buoytypes.Table
 	styles     Styles
 	viewAction ViewActionFunc
+	restore    *State
+	sortBy     []buoytypes.SortKey
+	filterbar  textinput.Model
+	filtering  bool
+	regex      bool
+	filter     *rowFilter
+	matched    int
+	height     int
+	multiline  bool
+	// widths are the widths of the auto sized columns
+	widths []int
+	// errored is the number of rows with cells that
+	// couldn't be computed, shown above the table
+	errored int
+}
+
+// State is the part of the table that is saved between sessions
+type State struct {
+	// Highlighted is the namespace/name of the highlighted row
+	Highlighted string `json:"highlighted,omitempty"`
+	// Page is the page that was being viewed, starting from 1
+	Page int `json:"page,omitempty"`
+	// Sort is the order of the rows, if it was
+	// changed from the order of the panel
+	Sort []buoytypes.SortKey `json:"sort,omitempty"`
+	// Filter is the term rows are filtered by, if any
+	Filter string `json:"filter,omitempty"`
+	Regex  bool   `json:"regex,omitempty"`
+	// Multiline is whether or not long values are wrapped,
+	// if it was toggled from the setting of the panel
+	Multiline *bool `json:"multiline,omitempty"`
 }
 
 func New(keys KeyMap, table *buoytypes.Table, styles Styles) *Model {
-	tblColumns := []tbl.Column{}
-	width := 0
-	for _, column := range table.Columns {
-		if column.Width > 0 {
-			tblColumns = append(tblColumns, tbl.NewColumn(column.Header, column.Header, column.Width))
-			width += column.Width
-		} else {
-			tblColumns = append(tblColumns, tbl.NewFlexColumn(column.Header, column.Header, 1))
-			width += defaultColumnWidth
-		}
-	}
+	sortBy := normalizeSort(table.SortBy)
+	tblColumns, width := tableColumns(table.Columns, sortBy, nil)
+	multiline := !table.SingleLine
+
+	// the table scrolls with the keys of the panel instead
+	tableKeys := tbl.DefaultKeyMap()
+	tableKeys.ScrollLeft = key.NewBinding(key.WithDisabled())
+	tableKeys.ScrollRight = key.NewBinding(key.WithDisabled())
 
 	pageSize := table.PageSize
 	if pageSize <= 0 {
@@ -113,43 +257,142 @@ func New(keys KeyMap, table *buoytypes.Table, styles Styles) *Model {
 		HighlightStyle(styles.SelectedRow).
 		WithBaseStyle(styles.TextAlignment).
 		WithPageSize(pageSize).
-		WithHorizontalFreezeColumnCount(1).
-		WithMultiline(true).
+		WithKeyMap(tableKeys).
+		WithHorizontalFreezeColumnCount(freezeColumns(table)).
+		WithMultiline(multiline).
 		WithTargetWidth(width).
 		BorderRounded()
 
+	extractors, err := compileColumns(table.Columns)
+	formats, formatErr := compileFormats(table.Columns)
+	cellStyles, cellStyleErr := compileCellStyles(table.Columns, styles.Palette)
+	rowStyles, rowStyleErr := compileRowStyles(table.RowStyles, styles.Palette)
+	labelSelector, fieldSelector, selectorErr := compileSelectors(table)
+	err = errors.Join(err, formatErr, cellStyleErr, rowStyleErr, selectorErr)
+	ticking := false
+	for _, column := range table.Columns {
+		ticking = ticking || column.Format == FormatAge
+	}
+
+	filterbar := textinput.New()
+	filterbar.Prompt = styles.FilterPrompt
+	filterbar.Placeholder = styles.FilterPlaceholder
+
 	return &Model{
-		tableModel: tab,
-		viewport:   viewport.New(0, 0),
-		mode:       modeTable,
-		mutex:      &sync.Mutex{},
-		rows:       map[types.UID]*RowInfo{},
-		columns:    table.Columns,
-		keys:       keys,
-		table:      table,
-		styles:     styles,
+		tableModel:    tab,
+		viewport:      viewport.New(0, 0),
+		mode:          modeTable,
+		mutex:         &sync.Mutex{},
+		rows:          map[types.UID]*RowInfo{},
+		columns:       table.Columns,
+		extractors:    extractors,
+		formats:       formats,
+		cellStyles:    cellStyles,
+		rowStyles:     rowStyles,
+		labelSelector: labelSelector,
+		fieldSelector: fieldSelector,
+		ticking:       ticking,
+		err:           err,
+		keys:          keys,
+		table:         table,
+		styles:        styles,
+		sortBy:        sortBy,
+		filterbar:     filterbar,
+		multiline:     multiline,
 	}
 }
 
+// tableColumns returns the columns of the table component, marking
+// the column that is sorted by, and the width the columns take up.
+// Auto sized columns are as wide as widths, or their header if
+// their widths aren't known yet.
+func tableColumns(columns []buoytypes.Column, sortBy []buoytypes.SortKey, widths []int) ([]tbl.Column, int) {
+	tblColumns := []tbl.Column{}
+	width := 0
+	for i, column := range columns {
+		title := column.Header + sortIndicator(column.Header, sortBy)
+		switch {
+		case column.Width == buoytypes.ColumnWidthAuto:
+			columnWidth := lipgloss.Width(title)
+			if i < len(widths) {
+				columnWidth = widths[i]
+			}
+			tblColumns = append(tblColumns, tbl.NewColumn(column.Header, title, columnWidth))
+			width += columnWidth
+		case column.Width > 0:
+			tblColumns = append(tblColumns, tbl.NewColumn(column.Header, title, int(column.Width)))
+			width += int(column.Width)
+		default:
+			tblColumns = append(tblColumns, tbl.NewFlexColumn(column.Header, title, 1))
+			width += defaultColumnWidth
+		}
+	}
+	return tblColumns, width
+}
+
+// setSort changes the order of the rows
+func (m *Model) setSort(sortBy []buoytypes.SortKey) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sortBy = sortBy
+	for _, rowInfo := range m.sorted {
+		rowInfo.sortValues = sortValues(rowInfo.values, m.sortBy, m.columns)
+	}
+	sortRows(m.sorted, m.sortBy)
+	m.setColumns()
+	m.dirty = true
+}
+
+// setColumns updates the headers and widths of the
+// columns. The mutex must be held by the caller.
+func (m *Model) setColumns() {
+	tblColumns, width := tableColumns(m.columns, m.sortBy, m.widths)
+	m.tableModel = m.tableModel.WithColumns(tblColumns).WithTargetWidth(width)
+}
+
+// setMultiline wraps long values over several
+// lines, or cuts them short if multiline is false
+func (m *Model) setMultiline(multiline bool) {
+	m.multiline = multiline
+	m.tableModel = m.tableModel.WithMultiline(multiline)
+}
+
 func (m *Model) Init() tea.Cmd {
 	return nil
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if err, ok := helper.ErrorFor(msg, m); ok {
+		m.SetError(err)
+		return m, nil
+	}
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case time.Time:
+		m.tick(msg)
 	case tea.WindowSizeMsg:
 		m.tableModel = m.tableModel.WithMaxTotalWidth(msg.Width)
 		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height / 2
+		m.viewport.Height = msg.Height
+		m.height = msg.Height
+		m.resize()
 	case tea.KeyMsg:
+		// every key press goes to the filter
+		// prompt while it is being typed into
+		if m.filtering {
+			return m, m.updateFilter(msg)
+		}
 		switch {
-		case key.Matches(msg, DefaultKeys.ViewModeToggle):
+		case key.Matches(msg, m.keys.ViewModeToggle):
 			switch m.mode {
 			case modeTable:
 				m.mode = modeView
 				m.tableModel = m.tableModel.Focused(false)
-				row := m.FetchRowForIndex(m.tableModel.GetHighlightedRowIndex())
+				row := m.HighlightedRow()
+				if m.viewAction == nil || row == nil {
+					m.viewport.SetContent("no resource selected")
+					break
+				}
 				vpContent, err := m.viewAction(row)
 				if err != nil {
 					m.viewport.SetContent(err.Error())
@@ -161,77 +404,412 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewport.SetContent("")
 				m.tableModel = m.tableModel.Focused(true)
 			}
+		case key.Matches(msg, m.keys.InspectRow):
+			switch m.mode {
+			case modeTable:
+				m.mode = modeInspect
+				m.tableModel = m.tableModel.Focused(false)
+				row := m.HighlightedRow()
+				if row == nil {
+					m.viewport.SetContent("no resource selected")
+					break
+				}
+				m.viewport.SetContent(m.inspect(row, time.Now()))
+			case modeInspect:
+				m.mode = modeTable
+				m.viewport.SetContent("")
+				m.tableModel = m.tableModel.Focused(true)
+			}
+		case key.Matches(msg, m.keys.ScrollLeft) && m.mode == modeTable:
+			m.tableModel = m.tableModel.ScrollLeft()
+		case key.Matches(msg, m.keys.ScrollRight) && m.mode == modeTable:
+			m.tableModel = m.tableModel.ScrollRight()
+		case key.Matches(msg, m.keys.ToggleWrap) && m.mode == modeTable:
+			m.setMultiline(!m.multiline)
+		case key.Matches(msg, m.keys.SortNext) && m.mode == modeTable:
+			m.setSort(nextSort(m.columns, m.sortBy))
+		case key.Matches(msg, m.keys.SortReverse) && m.mode == modeTable:
+			m.setSort(reverseSort(m.columns, m.sortBy))
+		case key.Matches(msg, m.keys.Filter) && m.mode == modeTable:
+			m.filtering = true
+			m.restore = nil
+			m.filterbar.Focus()
+			m.resize()
+			return m, textinput.Blink
+		case key.Matches(msg, m.keys.ClearFilter) && m.mode == modeTable && m.filterbar.Value() != "":
+			m.filterbar.SetValue("")
+			m.setFilter("")
+			m.resize()
 		}
 	}
 
-	if len(m.tempRows) > 0 {
-		m.tableModel = m.tableModel.WithRows(m.tempRows)
-		m.tempRows = []tbl.Row{}
-	}
+	m.applyRows()
+	m.restoreState(msg)
 
 	switch m.mode {
 	case modeTable:
 		m.tableModel = m.tableModel.Focused(true)
+		if mouse, ok := msg.(tea.MouseMsg); ok {
+			m.mouse(mouse)
+			break
+		}
 		m.tableModel, cmd = m.tableModel.Update(msg)
-	case modeView:
+	case modeView, modeInspect:
 		m.viewport, cmd = m.viewport.Update(msg)
 	}
 	return m, cmd
 }
 
-func (m *Model) View() string {
-	if m.err != nil {
-		return m.err.Error()
+// updateFilter types into the filter prompt, filtering
+// the rows by what has been typed so far
+func (m *Model) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, m.keys.SubmitFilter):
+		m.filtering = false
+		m.filterbar.Blur()
+	case key.Matches(msg, m.keys.ClearFilter):
+		m.filtering = false
+		m.filterbar.Blur()
+		m.filterbar.SetValue("")
+	case key.Matches(msg, m.keys.ToggleRegex):
+		m.regex = !m.regex
+	default:
+		m.filterbar, cmd = m.filterbar.Update(msg)
+	}
+	m.setFilter(m.filterbar.Value())
+	m.resize()
+	m.applyRows()
+	return cmd
+}
+
+// setFilter only shows the rows that match term
+func (m *Model) setFilter(term string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.filter = nil
+	if term != "" {
+		m.filter = newRowFilter(term, m.regex, m.columns)
+	}
+	for _, rowInfo := range m.sorted {
+		rowInfo.matched = m.filter == nil || m.filter.matches(rowInfo.Row)
+	}
+	m.dirty = true
+}
+
+// filterShown returns whether or not the filter
+// is shown above the table
+func (m *Model) filterShown() bool {
+	return m.filtering || m.filterbar.Value() != ""
+}
+
+// resize fits as many rows on a page as there is room
+// for, unless the panel has a fixed page size
+func (m *Model) resize() {
+	if m.table.PageSize > 0 || m.height <= 0 {
+		return
+	}
+	height := m.height - tableFrameHeight - m.bannerHeight()
+	m.tableModel = m.tableModel.WithPageSize(max(1, height))
+}
+
+// tableHeaderHeight is the number of lines used
+// by the top border and header above the rows
+const tableHeaderHeight = 3
+
+// mouse highlights the row that is clicked and moves
+// the highlighted row when the mouse wheel is scrolled
+func (m *Model) mouse(msg tea.MouseMsg) {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.tableModel = m.tableModel.WithHighlightedRow(m.tableModel.GetHighlightedRowIndex() - 1)
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.tableModel = m.tableModel.WithHighlightedRow(m.tableModel.GetHighlightedRowIndex() + 1)
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		if index, ok := m.rowAt(msg.Y); ok {
+			m.tableModel = m.tableModel.WithHighlightedRow(index)
+		}
+	}
+}
+
+// rowAt returns the index of the row rendered on line y of the
+// current page. Rows are as tall as their tallest cell value.
+func (m *Model) rowAt(y int) (int, bool) {
+	top := tableHeaderHeight + m.bannerHeight()
+	line := top
+	rows := m.tableModel.GetVisibleRows()
+	start, end := m.tableModel.VisibleIndices()
+	for i := start; i <= end && i < len(rows); i++ {
+		height := 1
+		if m.multiline {
+			height = rowHeight(rows[i])
+		}
+		line += height
+		if y < line {
+			return i, y >= top
+		}
+	}
+	return 0, false
+}
+
+func rowHeight(row tbl.Row) int {
+	height := 1
+	for _, value := range row.Data {
+		if cell, ok := value.(tbl.StyledCell); ok {
+			value = cell.Data
+		}
+		height = max(height, lipgloss.Height(fmt.Sprint(value)))
 	}
+	return height
+}
+
+func (m *Model) View() string {
 	switch m.mode {
 	case modeTable:
-		return m.tableModel.View()
-	case modeView:
+		banners := m.banners()
+		if len(banners) == 0 {
+			return m.tableModel.View()
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, append(banners, m.tableModel.View())...)
+	case modeView, modeInspect:
 		return m.viewport.View()
 	default:
 		return fmt.Sprintf("unknown table state. table.mode=%q", m.mode)
 	}
 }
 
+// filterView returns the filter prompt while it is being typed
+// into, or the applied filter, followed by the filter mode and
+// the number of rows that match
+func (m *Model) filterView() string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	mode := "fuzzy"
+	if m.regex {
+		mode = "regex"
+	}
+	status := fmt.Sprintf("%s, %d/%d rows", mode, m.matched, len(m.rows))
+	if m.filter != nil && m.filter.err != nil {
+		status = fmt.Sprintf("%s, %s", mode, m.filter.err)
+	}
+	if m.filtering {
+		return m.filterbar.View() + " " + m.styles.FilterStyle.Render("("+status+")")
+	}
+	return m.styles.FilterStyle.Render(fmt.Sprintf("filter: %s (%s)", m.filterbar.Value(), status))
+}
+
+// banners returns the lines shown above the table: the error
+// keeping the table from being updated, the filter and the
+// number of rows with cells that couldn't be computed
+func (m *Model) banners() []string {
+	banners := []string{}
+	if m.err != nil {
+		banners = append(banners, m.errView())
+	}
+	if m.filterShown() {
+		banners = append(banners, m.filterView())
+	}
+	if m.errored > 0 {
+		banners = append(banners, m.erroredView())
+	}
+	return banners
+}
+
+// bannerHeight returns the number of lines shown above the table
+func (m *Model) bannerHeight() int {
+	height := 0
+	if m.err != nil {
+		height += lipgloss.Height(m.errView())
+	}
+	if m.filterShown() {
+		height++
+	}
+	if m.errored > 0 {
+		height++
+	}
+	return height
+}
+
+// errView returns the error keeping the table from being updated
+func (m *Model) errView() string {
+	return m.styles.CellErrorStyle.Render(m.err.Error())
+}
+
+// erroredView returns the number of rows with cells
+// that couldn't be computed and how to inspect them
+func (m *Model) erroredView() string {
+	rows := "rows have"
+	if m.errored == 1 {
+		rows = "row has"
+	}
+	return m.styles.CellErrorStyle.Render(fmt.Sprintf("%s %d %s cells with errors, press %s on a row to inspect them",
+		cellErrorMarker, m.errored, rows, m.keys.InspectRow.Help().Key))
+}
+
 func (m *Model) AddOrUpdate(u *unstructured.Unstructured) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	uid := u.GetUID()
+	values := tbl.RowData{}
+	obj := newObject(u.Object)
+	for i, extractor := range m.extractors {
+		column := m.columns[i]
+		val, err := extractor.extract(obj)
+		if err != nil {
+			// only the cell fails, the rest of the row is still shown
+			val = cellError{err: err}
+		}
+		values[column.Header] = val
+	}
+
+	identifier := &types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}
+	rowInfo := &RowInfo{
+		UID:        uid,
+		Identifier: identifier,
+		values:     values,
+		style:      m.rowStyle(obj),
+		name:       identifier.String(),
+		sortValues: sortValues(values, m.sortBy, m.columns),
+	}
+	rowInfo.errors = rowInfo.cellErrors()
+	m.format(rowInfo, time.Now())
+	// only the updated row is moved instead of sorting every row again
+	if old, ok := m.rows[uid]; ok {
+		m.sorted = removeRow(m.sorted, old, m.sortBy)
+	}
+	m.sorted = insertRow(m.sorted, rowInfo, m.sortBy)
+	m.rows[uid] = rowInfo
+	m.dirty = true
+	m.RecordActivity(helper.AttentionNone)
+}
+
+// format formats the row, measuring its auto sized
+// cells and checking whether it matches the filter
+func (m *Model) format(rowInfo *RowInfo, now time.Time) {
+	rowInfo.Row = m.formatRow(rowInfo, now)
+	rowInfo.widths = cellWidths(m.columns, rowInfo.Row)
+	rowInfo.matched = m.filter == nil || m.filter.matches(rowInfo.Row)
+}
+
+// formatRow returns the row showing the formatted values of each
+// column, styled by the first style rule each value matches
+func (m *Model) formatRow(rowInfo *RowInfo, now time.Time) tbl.Row {
 	rowData := tbl.RowData{}
-	for _, column := range m.Columns() {
-		val, err := getDotNotationValue(u.Object, column.Path)
+	for i, column := range m.columns {
+		raw, ok := rowInfo.values[column.Header]
+		if !ok {
+			continue
+		}
+		rowData[column.Header] = m.renderCell(i, raw, now)
+	}
+	return tbl.NewRow(rowData).WithStyle(rowInfo.style.Copy().Inherit(m.styles.TextAlignment))
+}
+
+// rowStyle returns the style of the first row
+// style rule that the object matches
+func (m *Model) rowStyle(obj *object) lipgloss.Style {
+	for _, rowStyle := range m.rowStyles {
+		val, err := rowStyle.extractor.extract(obj)
 		if err != nil {
-			m.SetError(err)
-			break
+			continue
+		}
+		if rowStyle.rule.matchesValue(val) {
+			return rowStyle.rule.style
 		}
-		rowData[column.Header] = val
 	}
-	row := tbl.NewRow(rowData)
-	row = row.WithStyle(m.styles.TextAlignment)
+	return lipgloss.NewStyle()
+}
 
-	m.rows[uid] = &RowInfo{
-		Row:        row,
-		Identifier: &types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()},
+// tick formats the rows again once a second
+// so that ages keep counting up between updates
+func (m *Model) tick(now time.Time) {
+	if !m.ticking || now.Sub(m.lastTick) < time.Second {
+		return
+	}
+	m.lastTick = now
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, rowInfo := range m.sorted {
+		m.format(rowInfo, now)
 	}
-	m.updateRows()
+	m.dirty = true
 }
 
 func (m *Model) DeleteRow(uid types.UID) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	if rowInfo, ok := m.rows[uid]; ok {
+		m.sorted = removeRow(m.sorted, rowInfo, m.sortBy)
+	}
 	delete(m.rows, uid)
-	m.updateRows()
+	m.dirty = true
+	m.RecordActivity(helper.AttentionNone)
 }
 
-func (m *Model) updateRows() {
-	rows := []tbl.Row{}
-	indice := 0
-	for _, rowInfo := range m.rows {
+// applyRows shows the rows if they changed since they were last
+// shown, keeping the same object highlighted wherever it has moved
+// to. If the highlighted object was deleted the highlight stays at
+// the same position, moving to the last row if it was the last row.
+// The rows are built from the sorted rows using what was computed
+// for each row when it was added or formatted.
+func (m *Model) applyRows() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.dirty {
+		return
+	}
+	m.dirty = false
+	highlighted := m.highlightedUID()
+
+	rows := make([]tbl.Row, 0, len(m.sorted))
+	uids := make([]types.UID, 0, len(m.sorted))
+	cellWidths := make([][]int, 0, len(m.sorted))
+	errored := 0
+	for _, rowInfo := range m.sorted {
+		if rowInfo.errors > 0 {
+			errored++
+		}
+		rowInfo.Index = -1
+		if !rowInfo.matched {
+			continue
+		}
+		rowInfo.Index = len(rows)
 		rows = append(rows, rowInfo.Row)
-		rowInfo.Index = indice
-		indice++
+		uids = append(uids, rowInfo.UID)
+		cellWidths = append(cellWidths, rowInfo.widths)
+	}
+	m.matched = len(rows)
+
+	if m.errored != errored {
+		m.errored = errored
+		m.resize()
+	}
+	if widths := autoWidths(m.columns, m.sortBy, cellWidths); !slices.Equal(m.widths, widths) {
+		m.widths = widths
+		m.setColumns()
+	}
+	m.tableModel = m.tableModel.WithRows(rows)
+	m.shown = uids
+	if index := slices.Index(m.shown, highlighted); index >= 0 {
+		m.tableModel = m.tableModel.WithHighlightedRow(index)
+	}
+}
+
+// highlightedUID returns the UID of the highlighted row
+// as it is shown. The mutex must be held by the caller.
+func (m *Model) highlightedUID() types.UID {
+	index := m.tableModel.GetHighlightedRowIndex()
+	if index < 0 || index >= len(m.shown) {
+		return ""
 	}
-	m.tempRows = rows
+	return m.shown[index]
+}
+
+// Count returns the number of rows in the table
+func (m *Model) Count() (int, string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return len(m.rows), "rows"
 }
 
 func (m *Model) Columns() []buoytypes.Column {
@@ -250,50 +828,200 @@ func (m *Model) GVK() schema.GroupVersionKind {
 	}
 }
 
-func (m *Model) Namespace() string {
-	return m.table.Namespace
+// Namespaces returns the namespaces the table shows
+// resources of, where the empty namespace is every namespace
+func (m *Model) Namespaces() []string {
+	return namespacesFor(m.table)
 }
 
-func (m *Model) LabelSelector() labels.Set {
-	return m.table.LabelSelector
+func (m *Model) LabelSelector() labels.Selector {
+	return m.labelSelector
+}
+
+func (m *Model) FieldSelector() fields.Selector {
+	return m.fieldSelector
+}
+
+// MetadataOnly returns whether or not all of the columns
+// in the table only read fields from the object metadata
+func (m *Model) MetadataOnly() bool {
+	if len(m.columns) == 0 {
+		return false
+	}
+	for _, column := range m.columns {
+		if !readsMetadataOnly(column.Path) {
+			return false
+		}
+	}
+	for _, rowStyle := range m.table.RowStyles {
+		if !readsMetadataOnly(rowStyle.Path) {
+			return false
+		}
+	}
+	return true
 }
 
+func (m *Model) PrunePaths() []string {
+	return m.table.Prune
+}
+
+// SetError shows err above the table, or stops showing the
+// error shown if err is nil
 func (m *Model) SetError(err error) {
 	m.err = err
+	m.resize()
 }
 
+// FetchRowForIndex returns the row shown at index
 func (m *Model) FetchRowForIndex(index int) *RowInfo {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	var rowInfo *RowInfo
-	for _, row := range m.rows {
-		if row.Index == index {
-			rowInfo = row
-			break
-		}
+	if index < 0 || index >= len(m.shown) {
+		return nil
 	}
+	return m.rows[m.shown[index]]
+}
+
+// HighlightedRow returns the row that is highlighted,
+// or nil if the table doesn't have any rows
+func (m *Model) HighlightedRow() *RowInfo {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.rows[m.highlightedUID()]
+}
 
-	return rowInfo
+// SaveState returns the highlighted row and page of the table
+func (m *Model) SaveState() (json.RawMessage, error) {
+	state := State{Page: m.tableModel.CurrentPage()}
+	if !slices.Equal(m.sortBy, normalizeSort(m.table.SortBy)) {
+		state.Sort = m.sortBy
+	}
+	state.Filter = m.filterbar.Value()
+	state.Regex = m.regex
+	if m.multiline == m.table.SingleLine {
+		state.Multiline = &m.multiline
+	}
+	if row := m.HighlightedRow(); row != nil {
+		state.Highlighted = row.Identifier.String()
+	}
+	return json.Marshal(state)
+}
+
+// RestoreState highlights the row that was highlighted in
+// a saved session once it has been added to the table
+func (m *Model) RestoreState(raw json.RawMessage) error {
+	state := &State{}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return fmt.Errorf("unmarshalling table state: %w", err)
+	}
+	m.restore = state
+	if len(state.Sort) > 0 && ValidateSort(&buoytypes.Table{Columns: m.columns, SortBy: state.Sort}) == nil {
+		m.setSort(state.Sort)
+	}
+	m.regex = state.Regex
+	if state.Multiline != nil {
+		m.setMultiline(*state.Multiline)
+	}
+	if state.Filter != "" {
+		m.filterbar.SetValue(state.Filter)
+		m.setFilter(state.Filter)
+	}
+	return nil
+}
+
+// restoreState highlights the saved row once it exists, going
+// to the saved page in the meantime. Any user input cancels
+// restoring so that the highlighted row isn't moved from under
+// the user.
+func (m *Model) restoreState(msg tea.Msg) {
+	if m.restore == nil {
+		return
+	}
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		m.restore = nil
+		return
+	}
+
+	m.mutex.Lock()
+	index := slices.IndexFunc(m.shown, func(uid types.UID) bool {
+		row, ok := m.rows[uid]
+		return ok && row.Identifier.String() == m.restore.Highlighted
+	})
+	m.mutex.Unlock()
+
+	if index >= 0 && index < len(m.tableModel.GetVisibleRows()) {
+		m.tableModel = m.tableModel.WithHighlightedRow(index)
+		m.restore = nil
+		return
+	}
+	if m.restore.Page > m.tableModel.CurrentPage() && m.restore.Page <= m.tableModel.MaxPages() {
+		m.tableModel = m.tableModel.WithCurrentPage(m.restore.Page)
+	}
 }
 
 func (m *Model) SetViewActionFunc(vaf ViewActionFunc) {
 	m.viewAction = vaf
 }
 
+// CapturingInput returns whether or not
+// a filter is currently being typed
+func (m *Model) CapturingInput() bool {
+	return m.filtering
+}
+
+// navigationKeys describes the keys the table
+// component uses to move between rows and pages
+var navigationKeys = []key.Binding{
+	key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move up")),
+	key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move down")),
+	key.NewBinding(key.WithKeys("left", "h", "pgup"), key.WithHelp("←/h/pgup", "previous page")),
+	key.NewBinding(key.WithKeys("right", "l", "pgdown"), key.WithHelp("→/l/pgdown", "next page")),
+	key.NewBinding(key.WithKeys("home", "g"), key.WithHelp("home/g", "first page")),
+	key.NewBinding(key.WithKeys("end", "G"), key.WithHelp("end/G", "last page")),
+}
+
+// Help returns the keys of the table followed by the keys
+// that can be used in the current mode. Only the keys of
+// the current mode are shown in the mini help view.
 func (m *Model) Help() help.KeyMap {
-	return m.keys
+	browsing := []key.Binding{m.keys.ViewModeToggle, m.keys.Filter}
+	if m.errored > 0 {
+		browsing = append(browsing, m.keys.InspectRow)
+	}
+	if m.filterbar.Value() != "" {
+		browsing = append(browsing, m.keys.ClearFilter)
+	}
+	modeHelp := helper.NewScopedHelpKeyMap("table: browsing rows",
+		helper.NewBindingsHelpKeyMap(browsing, append(slices.Clone(navigationKeys), m.keys.ScrollLeft, m.keys.ScrollRight, m.keys.ToggleWrap)))
+	switch {
+	case m.filtering:
+		filtering := []key.Binding{m.keys.SubmitFilter, m.keys.ClearFilter, m.keys.ToggleRegex}
+		modeHelp = helper.NewScopedHelpKeyMap("table: typing a filter",
+			helper.NewBindingsHelpKeyMap(filtering, filtering))
+	case m.mode == modeView:
+		vk := m.viewport.KeyMap
+		modeHelp = helper.NewScopedHelpKeyMap("table: viewing resource",
+			helper.NewBindingsHelpKeyMap([]key.Binding{m.keys.ViewModeToggle},
+				[]key.Binding{m.keys.ViewModeToggle, vk.Up, vk.Down, vk.PageUp, vk.PageDown, vk.HalfPageUp, vk.HalfPageDown}))
+	case m.mode == modeInspect:
+		vk := m.viewport.KeyMap
+		modeHelp = helper.NewScopedHelpKeyMap("table: inspecting resource",
+			helper.NewBindingsHelpKeyMap([]key.Binding{m.keys.InspectRow},
+				[]key.Binding{m.keys.InspectRow, vk.Up, vk.Down, vk.PageUp, vk.PageDown, vk.HalfPageUp, vk.HalfPageDown}))
+	}
+	return helper.NewCompositeHelpKeyMap(
+		helper.NewScopedHelpKeyMap("table", m.keys),
+		modeHelp,
+	)
 }
 
 func getDotNotationValue(item map[string]interface{}, dotPath string) (interface{}, error) {
-	jsonBytes, err := json.Marshal(item)
+	extractor, err := compilePath(dotPath)
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling item to json: %w", err)
-	}
-	res := gjson.Get(string(jsonBytes), dotPath)
-	if !res.Exists() {
-		return "n/a", nil
+		return nil, err
 	}
-	return res.Value(), nil
+	return extractor.extract(newObject(item))
 }
 
 func highlight(s string, styles Styles) string {
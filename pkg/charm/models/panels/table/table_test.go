@@ -2,9 +2,13 @@ package table
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 	buoytypes "github.com/everettraven/buoy/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -16,7 +20,7 @@ func TestTableUpdate(t *testing.T) {
 	table := New(DefaultKeys, &buoytypes.Table{}, Styles{})
 	table.Update(tea.WindowSizeMsg{Width: 50, Height: 50})
 	assert.Equal(t, 50, table.viewport.Width)
-	assert.Equal(t, 25, table.viewport.Height)
+	assert.Equal(t, 50, table.viewport.Height)
     table.SetViewActionFunc(func(row *RowInfo) (string, error) {
         return "", nil
     })
@@ -30,6 +34,19 @@ func TestTableUpdate(t *testing.T) {
 	assert.Equal(t, modeTable, table.mode)
 }
 
+func TestTableCustomKeys(t *testing.T) {
+	keys := KeyMap{ViewModeToggle: key.NewBinding(key.WithKeys("enter"))}
+	table := New(keys, &buoytypes.Table{}, Styles{})
+
+	t.Log("the default key does nothing")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	assert.Equal(t, modeTable, table.mode)
+
+	t.Log("the custom key toggles view mode")
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, modeView, table.mode)
+}
+
 func TestAddOrUpdate(t *testing.T) {
 	table := New(DefaultKeys, &buoytypes.Table{
 		Columns: []buoytypes.Column{
@@ -81,10 +98,14 @@ func TestTableView(t *testing.T) {
 	t.Log("view with error state")
 	err := errors.New("some error")
 	table.SetError(err)
-	assert.Equal(t, err.Error(), table.View())
+	assert.Equal(t, lipgloss.JoinVertical(lipgloss.Left, err.Error(), table.tableModel.View()), table.View())
+
+	t.Log("errors sent to other panels are ignored")
+	table.Update(helper.ErrorMsg{Panel: New(DefaultKeys, &buoytypes.Table{}, Styles{}), Err: errors.New("other error")})
+	assert.Equal(t, lipgloss.JoinVertical(lipgloss.Left, err.Error(), table.tableModel.View()), table.View())
 
 	t.Log("view with table mode")
-	table.SetError(nil)
+	table.Update(helper.ErrorMsg{Panel: table, Err: nil})
 	table.mode = modeTable
 	assert.Equal(t, table.tableModel.View(), table.View())
 
@@ -103,5 +124,460 @@ func TestGetDotNotationValueNonExistentValue(t *testing.T) {
 
 	val, err := getDotNotationValue(obj, "foo.baz")
 	assert.NoError(t, err)
-	assert.Equal(t, "n/a", val)
+	assert.Equal(t, missingValue, val)
+}
+
+func TestMetadataOnly(t *testing.T) {
+	table := New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Path: "metadata.name"},
+			{Header: "Labels", Path: "metadata.labels"},
+		},
+	}, Styles{})
+	assert.True(t, table.MetadataOnly())
+
+	table = New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Path: "metadata.name"},
+			{Header: "Phase", Path: "status.phase"},
+		},
+	}, Styles{})
+	assert.False(t, table.MetadataOnly())
+
+	table = New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Path: "jsonpath:.metadata.name"},
+			{Header: "Labels", Path: "jsonpath:{.metadata.labels}"},
+		},
+	}, Styles{})
+	assert.True(t, table.MetadataOnly())
+
+	table = New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Path: "metadata.name"},
+		},
+		RowStyles: []buoytypes.RowStyleRule{
+			{Path: "status.phase", StyleRule: buoytypes.StyleRule{Equals: "Failed"}},
+		},
+	}, Styles{})
+	assert.False(t, table.MetadataOnly())
+}
+
+func TestTablePageSize(t *testing.T) {
+	t.Log("page size is derived from the height when unset")
+	table := New(DefaultKeys, &buoytypes.Table{}, Styles{})
+	table.Update(tea.WindowSizeMsg{Width: 50, Height: 30})
+	assert.Equal(t, 30-tableFrameHeight, table.tableModel.PageSize())
+
+	t.Log("configured page size is kept")
+	table = New(DefaultKeys, &buoytypes.Table{PageSize: 3}, Styles{})
+	table.Update(tea.WindowSizeMsg{Width: 50, Height: 30})
+	assert.Equal(t, 3, table.tableModel.PageSize())
+}
+
+func TestTableMouse(t *testing.T) {
+	table := New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Width: 10, Path: "metadata.name"},
+		},
+	}, Styles{})
+	for _, name := range []string{"a", "b", "c"} {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetUID(types.UID(name))
+		table.AddOrUpdate(u)
+	}
+	table.Update(tea.WindowSizeMsg{Width: 50, Height: 30})
+
+	t.Log("clicking a row highlights it")
+	table.Update(tea.MouseMsg{X: 2, Y: tableHeaderHeight + 2, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 2, table.tableModel.GetHighlightedRowIndex())
+
+	t.Log("clicking the header does nothing")
+	table.Update(tea.MouseMsg{X: 2, Y: 1, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 2, table.tableModel.GetHighlightedRowIndex())
+
+	t.Log("scrolling moves the highlighted row")
+	table.Update(tea.MouseMsg{X: 2, Y: 5, Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	assert.Equal(t, 1, table.tableModel.GetHighlightedRowIndex())
+	table.Update(tea.MouseMsg{X: 2, Y: 5, Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	assert.Equal(t, 2, table.tableModel.GetHighlightedRowIndex())
+}
+
+func TestTableState(t *testing.T) {
+	columns := []buoytypes.Column{{Header: "Name", Width: 10, Path: "metadata.name"}}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	u := &unstructured.Unstructured{}
+	u.SetName("test")
+	u.SetNamespace("test-ns")
+	u.SetUID(types.UID("test"))
+	table.AddOrUpdate(u)
+	table.Update(nil)
+
+	t.Log("the highlighted row is saved by name")
+	raw, err := table.SaveState()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"highlighted":"test-ns/test","page":1}`, string(raw))
+
+	t.Log("the saved row is highlighted once it is added")
+	restored := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	assert.NoError(t, restored.RestoreState(raw))
+	for _, name := range []string{"a", "b", "test"} {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetNamespace("test-ns")
+		u.SetUID(types.UID(name))
+		restored.AddOrUpdate(u)
+		restored.Update(nil)
+	}
+	row := restored.FetchRowForIndex(restored.tableModel.GetHighlightedRowIndex())
+	assert.Equal(t, "test-ns/test", row.Identifier.String())
+	assert.Nil(t, restored.restore)
+
+	t.Log("user input cancels restoring")
+	restored = New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	assert.NoError(t, restored.RestoreState(raw))
+	restored.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Nil(t, restored.restore)
+}
+
+func TestTableSort(t *testing.T) {
+	columns := []buoytypes.Column{
+		{Header: "Name", Width: 10, Path: "metadata.name"},
+		{Header: "CPU", Width: 10, Path: "metadata.labels.cpu"},
+		{Header: "Created", Width: 10, Path: "metadata.annotations.created"},
+	}
+	objects := []struct {
+		name    string
+		cpu     string
+		created string
+	}{
+		{name: "a", cpu: "2Gi", created: "2024-01-03T00:00:00Z"},
+		{name: "b", cpu: "500m", created: "2024-01-01T00:00:00Z"},
+		{name: "c", cpu: "", created: "2024-01-02T00:00:00Z"},
+		{name: "d", cpu: "1", created: "2023-12-31T00:00:00Z"},
+	}
+	newTable := func(sortBy ...buoytypes.SortKey) *Model {
+		table := New(DefaultKeys, &buoytypes.Table{Columns: columns, SortBy: sortBy}, Styles{})
+		for _, obj := range objects {
+			u := &unstructured.Unstructured{}
+			u.SetName(obj.name)
+			u.SetNamespace("test-ns")
+			u.SetUID(types.UID(obj.name))
+			if obj.cpu != "" {
+				u.SetLabels(map[string]string{"cpu": obj.cpu})
+			}
+			u.SetAnnotations(map[string]string{"created": obj.created})
+			table.AddOrUpdate(u)
+		}
+		return table
+	}
+	order := func(table *Model) []string {
+		// rows are ordered when they are shown
+		table.Update(nil)
+		ordered := make([]string, len(table.rows))
+		for _, row := range table.rows {
+			ordered[row.Index] = row.Identifier.Name
+		}
+		return ordered
+	}
+
+	t.Log("rows are ordered by name when no sort is set")
+	assert.Equal(t, []string{"a", "b", "c", "d"}, order(newTable()))
+
+	t.Log("quantities are ordered by value with missing values last")
+	assert.Equal(t, []string{"b", "d", "a", "c"}, order(newTable(buoytypes.SortKey{Column: "CPU"})))
+
+	t.Log("missing values stay last when descending")
+	assert.Equal(t, []string{"a", "d", "b", "c"}, order(newTable(buoytypes.SortKey{Column: "CPU", Order: buoytypes.SortDescending})))
+
+	t.Log("timestamps are ordered by time")
+	assert.Equal(t, []string{"d", "b", "c", "a"}, order(newTable(buoytypes.SortKey{Column: "Created"})))
+
+	t.Log("updated rows move to where their new values belong")
+	table := newTable(buoytypes.SortKey{Column: "CPU"})
+	u := &unstructured.Unstructured{}
+	u.SetName("b")
+	u.SetNamespace("test-ns")
+	u.SetUID(types.UID("b"))
+	u.SetLabels(map[string]string{"cpu": "4Gi"})
+	table.AddOrUpdate(u)
+	assert.Equal(t, []string{"d", "a", "b", "c"}, order(table))
+	table.DeleteRow(types.UID("a"))
+	assert.Equal(t, []string{"d", "b", "c"}, order(table))
+
+	t.Log("s sorts by the next column and shows it in the header")
+	table = newTable(buoytypes.SortKey{Column: "Name"})
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	assert.Equal(t, []buoytypes.SortKey{{Column: "CPU", Order: buoytypes.SortAscending}, {Column: "Name", Order: buoytypes.SortAscending}}, table.sortBy)
+	assert.Contains(t, table.View(), "CPU ▲")
+
+	t.Log("S reverses the sort order")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	assert.Equal(t, []string{"a", "d", "b", "c"}, order(table))
+	assert.Contains(t, table.View(), "CPU ▼")
+
+	t.Log("the sort is saved when it differs from the configured sort")
+	raw, err := table.SaveState()
+	assert.NoError(t, err)
+	restored := newTable(buoytypes.SortKey{Column: "Name"})
+	assert.NoError(t, restored.RestoreState(raw))
+	assert.Equal(t, table.sortBy, restored.sortBy)
+
+	t.Log("sorting by an unknown column or order is invalid")
+	assert.Error(t, ValidateSort(&buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "Missing"}}}))
+	assert.Error(t, ValidateSort(&buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "CPU", Order: "up"}}}))
+	assert.NoError(t, ValidateSort(&buoytypes.Table{Columns: columns, SortBy: []buoytypes.SortKey{{Column: "CPU", Order: buoytypes.SortDescending}}}))
+}
+
+func TestTableHighlightFollowsRow(t *testing.T) {
+	columns := []buoytypes.Column{{Header: "Name", Width: 10, Path: "metadata.name"}}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	objects := map[string]*unstructured.Unstructured{}
+	for _, name := range []string{"b", "c", "d"} {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetNamespace("test-ns")
+		u.SetUID(types.UID(name))
+		objects[name] = u
+		table.AddOrUpdate(u)
+	}
+	table.Update(nil)
+
+	t.Log("highlight the second row")
+	table.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, types.UID("c"), table.HighlightedRow().UID)
+
+	t.Log("the highlight stays on the object when a row is added before it")
+	u := &unstructured.Unstructured{}
+	u.SetName("a")
+	u.SetNamespace("test-ns")
+	u.SetUID(types.UID("a"))
+	table.AddOrUpdate(u)
+	assert.Equal(t, types.UID("c"), table.HighlightedRow().UID, "rows aren't shown until the next update")
+	table.Update(nil)
+	assert.Equal(t, types.UID("c"), table.HighlightedRow().UID)
+	assert.Equal(t, 2, table.tableModel.GetHighlightedRowIndex())
+
+	t.Log("the highlight stays on the object when it is renamed")
+	objects["c"].SetName("e")
+	table.AddOrUpdate(objects["c"])
+	table.Update(nil)
+	assert.Equal(t, types.UID("c"), table.HighlightedRow().UID)
+	assert.Equal(t, 3, table.tableModel.GetHighlightedRowIndex())
+
+	t.Log("the highlight stays at the last row when the highlighted object is deleted")
+	table.DeleteRow(types.UID("c"))
+	table.Update(nil)
+	assert.Equal(t, types.UID("d"), table.HighlightedRow().UID)
+
+	t.Log("the viewed object is the highlighted one")
+	var viewed types.UID
+	table.SetViewActionFunc(func(row *RowInfo) (string, error) {
+		viewed = row.UID
+		return "", nil
+	})
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	assert.Equal(t, types.UID("d"), viewed)
+
+	t.Log("deleting every row empties the table")
+	for _, uid := range []types.UID{"a", "b", "d"} {
+		table.DeleteRow(uid)
+	}
+	table.Update(nil)
+	assert.Empty(t, table.tableModel.GetVisibleRows())
+	assert.Nil(t, table.HighlightedRow())
+}
+
+func TestTableFilter(t *testing.T) {
+	columns := []buoytypes.Column{
+		{Header: "Name", Width: 10, Path: "metadata.name"},
+		{Header: "App", Width: 10, Path: "metadata.labels.app"},
+	}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	add := func(name, app string) {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetNamespace("test-ns")
+		u.SetUID(types.UID(name))
+		u.SetLabels(map[string]string{"app": app})
+		table.AddOrUpdate(u)
+	}
+	add("nginx-abc", "web")
+	add("redis-def", "cache")
+	add("api-ghi", "nginx")
+	table.Update(nil)
+	shown := func() []string {
+		names := []string{}
+		for i := range table.tableModel.GetVisibleRows() {
+			names = append(names, table.FetchRowForIndex(i).Identifier.Name)
+		}
+		return names
+	}
+	typeFilter := func(term string) {
+		for _, r := range term {
+			table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	t.Log("/ opens the filter prompt and captures input")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	assert.True(t, table.CapturingInput())
+
+	t.Log("rows are fuzzy matched across every column as the filter is typed")
+	typeFilter("ngx")
+	assert.Equal(t, []string{"api-ghi", "nginx-abc"}, shown())
+	assert.Contains(t, table.View(), "fuzzy, 2/3 rows")
+
+	t.Log("the filter stays applied after it is submitted")
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, table.CapturingInput())
+	assert.Contains(t, table.View(), "filter: ngx")
+
+	t.Log("rows that arrive later are filtered too")
+	add("nginx-xyz", "web")
+	add("postgres", "db")
+	table.Update(nil)
+	assert.Equal(t, []string{"api-ghi", "nginx-abc", "nginx-xyz"}, shown())
+
+	t.Log("clearing the filter shows every row")
+	table.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, shown(), 5)
+	assert.NotContains(t, table.View(), "filter:")
+
+	t.Log("column:term only matches the values of that column")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	typeFilter("app:nginx")
+	assert.Equal(t, []string{"api-ghi"}, shown())
+
+	t.Log("regex mode matches a regular expression")
+	table.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	table.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	typeFilter("^(redis|postgres)")
+	assert.Equal(t, []string{"postgres", "redis-def"}, shown())
+
+	t.Log("an invalid regex shows every row and the error")
+	typeFilter("(")
+	assert.Len(t, shown(), 5)
+	assert.Contains(t, table.View(), "invalid regex")
+
+	t.Log("the filter is saved and restored")
+	table.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	table.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	raw, err := table.SaveState()
+	assert.NoError(t, err)
+	restored := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	assert.NoError(t, restored.RestoreState(raw))
+	assert.Equal(t, "^(redis|postgres)", restored.filterbar.Value())
+	assert.True(t, restored.regex)
+}
+
+func TestTableCellErrors(t *testing.T) {
+	table := New(DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{
+			{Header: "Name", Width: 10, Path: "metadata.name"},
+			{Header: "Ready", Width: 10, Expr: "self.status.readyReplicas"},
+			{Header: "Node", Width: 10, Path: "spec.nodeName", Placeholder: "<none>"},
+		},
+	}, Styles{})
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetName("foo")
+	u.SetNamespace("test-ns")
+	u.SetUID(types.UID("foo"))
+
+	t.Log("a cell that fails shows a marker while the rest of the row is shown")
+	table.Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	table.AddOrUpdate(u)
+	table.Update(nil)
+	row := table.FetchRowForIndex(0)
+	assert.Equal(t, "foo", row.Row.Data["Name"])
+	assert.Contains(t, fmt.Sprint(row.Row.Data["Ready"]), "⚠ no such key: status")
+	assert.Contains(t, table.View(), "1 row has cells with errors")
+
+	t.Log("missing paths show the placeholder of the column")
+	assert.Equal(t, "<none>", row.Row.Data["Node"])
+
+	t.Log("the inspector shows the full error of every cell")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	assert.Equal(t, modeInspect, table.mode)
+	assert.Contains(t, table.View(), "test-ns/foo")
+	assert.Contains(t, table.View(), "⚠ no such key: status")
+	assert.Contains(t, table.View(), "<none> (not found)")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	assert.Equal(t, modeTable, table.mode)
+
+	t.Log("the errors clear once the object no longer fails")
+	assert.NoError(t, unstructured.SetNestedField(u.Object, int64(1), "status", "readyReplicas"))
+	table.AddOrUpdate(u)
+	table.Update(nil)
+	assert.Equal(t, int64(1), table.FetchRowForIndex(0).Row.Data["Ready"])
+	assert.NotContains(t, table.View(), "cells with errors")
+}
+
+func TestTableScrollAndWrap(t *testing.T) {
+	columns := []buoytypes.Column{
+		{Header: "Name", Width: buoytypes.ColumnWidthAuto, Path: "metadata.name"},
+		{Header: "Labels", Width: 30, Path: "metadata.labels", Format: "keyvalue:\n"},
+		{Header: "Namespace", Width: 30, Path: "metadata.namespace"},
+	}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	table.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	u := &unstructured.Unstructured{}
+	u.SetName("a-long-name")
+	u.SetNamespace("test-ns")
+	u.SetUID(types.UID("foo"))
+	u.SetLabels(map[string]string{"app": "foo", "tier": "web"})
+	table.AddOrUpdate(u)
+	table.Update(nil)
+
+	t.Log("auto columns are sized to fit their values")
+	assert.Equal(t, []int{11, 0, 0}, table.widths)
+
+	t.Log("the columns after the frozen column scroll horizontally")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(">")})
+	assert.Equal(t, 1, table.tableModel.GetHorizontalScrollColumnOffset())
+	assert.Contains(t, table.View(), "a-long-name")
+	assert.NotContains(t, table.View(), "Labels")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("<")})
+	assert.Equal(t, 0, table.tableModel.GetHorizontalScrollColumnOffset())
+
+	t.Log("wrapping can be toggled and is saved")
+	assert.Contains(t, table.View(), "tier=web")
+	table.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	assert.False(t, table.multiline)
+	assert.NotContains(t, table.View(), "tier=web")
+	raw, err := table.SaveState()
+	assert.NoError(t, err)
+	restored := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+	assert.NoError(t, restored.RestoreState(raw))
+	assert.False(t, restored.multiline)
+}
+
+func TestTableRowsBuiltOncePerRender(t *testing.T) {
+	columns := []buoytypes.Column{{Header: "Name", Path: "metadata.name", Width: buoytypes.ColumnWidthAuto}}
+	table := New(DefaultKeys, &buoytypes.Table{Columns: columns}, Styles{})
+
+	t.Log("events only update the changed row")
+	for _, name := range []string{"b", "a", "a-much-longer-name"} {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		u.SetUID(types.UID(name))
+		table.AddOrUpdate(u)
+	}
+	assert.True(t, table.dirty)
+	assert.Empty(t, table.tableModel.GetVisibleRows())
+
+	t.Log("the rows and widths are rebuilt when the table is next rendered")
+	table.Update(nil)
+	assert.False(t, table.dirty)
+	assert.Len(t, table.tableModel.GetVisibleRows(), 3)
+	assert.Equal(t, []int{len("a-much-longer-name")}, table.widths)
+	assert.Equal(t, "a", table.FetchRowForIndex(0).Identifier.Name)
+
+	t.Log("deleting a row marks the table to be rebuilt too")
+	table.DeleteRow(types.UID("a-much-longer-name"))
+	table.Update(nil)
+	assert.Len(t, table.tableModel.GetVisibleRows(), 2)
+	assert.Equal(t, []int{len("Name")}, table.widths)
 }
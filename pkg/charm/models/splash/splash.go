@@ -0,0 +1,147 @@
+package splash
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	figure "github.com/common-nighthawk/go-figure"
+)
+
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusReady
+	StatusFailed
+)
+
+// ClusterStatusMsg reports the status of
+// connecting to the Kubernetes cluster
+type ClusterStatusMsg struct {
+	Status Status
+	Err    error
+}
+
+// PanelStatusMsg reports the status of setting
+// up the datastream for the panel with Name
+type PanelStatusMsg struct {
+	Name   string
+	Status Status
+	Err    error
+}
+
+type Styles struct {
+	Logo  lipgloss.Style
+	Error lipgloss.Style
+}
+
+type step struct {
+	name   string
+	status Status
+	err    error
+}
+
+// Model is a tea.Model implementation
+// that shows the progress of setting
+// up the dashboard while it starts
+type Model struct {
+	cluster step
+	panels  []step
+	spinner spinner.Model
+	styles  Styles
+	width   int
+	height  int
+}
+
+func New(styles Styles, panelNames ...string) *Model {
+	panels := []step{}
+	for _, name := range panelNames {
+		panels = append(panels, step{name: name})
+	}
+	return &Model{
+		cluster: step{name: "connecting to cluster"},
+		panels:  panels,
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot)),
+		styles:  styles,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case ClusterStatusMsg:
+		m.cluster.status = msg.Status
+		m.cluster.err = msg.Err
+	case PanelStatusMsg:
+		for i := range m.panels {
+			if m.panels[i].name == msg.Name {
+				m.panels[i].status = msg.Status
+				m.panels[i].err = msg.Err
+			}
+		}
+	case spinner.TickMsg:
+		m.spinner, cmd = m.spinner.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	var out strings.Builder
+	fig := figure.NewFigure("buoy", "rounded", true)
+	out.WriteString(m.styles.Logo.Render(fig.String()) + "\n\n")
+	out.WriteString(m.renderStep(m.cluster) + "\n")
+	for _, panel := range m.panels {
+		out.WriteString(m.renderStep(panel) + "\n")
+	}
+	if m.Failed() {
+		out.WriteString("\nunable to start the dashboard, press q to quit\n")
+	} else {
+		out.WriteString("\npress any key to continue\n")
+	}
+
+	if m.width == 0 || m.height == 0 {
+		return out.String()
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, out.String())
+}
+
+func (m *Model) renderStep(s step) string {
+	switch s.status {
+	case StatusReady:
+		return fmt.Sprintf("✓ %s", s.name)
+	case StatusFailed:
+		return m.styles.Error.Render(fmt.Sprintf("✗ %s: %s", s.name, s.err))
+	default:
+		return fmt.Sprintf("%s%s", m.spinner.View(), s.name)
+	}
+}
+
+// Done returns whether or not every step
+// of setting up the dashboard has finished
+func (m *Model) Done() bool {
+	if m.cluster.status != StatusReady {
+		return false
+	}
+	for _, panel := range m.panels {
+		if panel.status == StatusPending {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns whether or not the dashboard
+// can not be started at all
+func (m *Model) Failed() bool {
+	return m.cluster.status == StatusFailed
+}
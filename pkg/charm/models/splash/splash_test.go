@@ -0,0 +1,36 @@
+package splash
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplashProgress(t *testing.T) {
+	s := New(Styles{}, "first", "second")
+	assert.False(t, s.Done())
+
+	t.Log("cluster connected")
+	s.Update(ClusterStatusMsg{Status: StatusReady})
+	assert.False(t, s.Done())
+
+	t.Log("first panel ready")
+	s.Update(PanelStatusMsg{Name: "first", Status: StatusReady})
+	assert.False(t, s.Done())
+	assert.Contains(t, s.View(), "✓ first")
+
+	t.Log("second panel failed")
+	s.Update(PanelStatusMsg{Name: "second", Status: StatusFailed, Err: errors.New("boom")})
+	assert.True(t, s.Done())
+	assert.False(t, s.Failed())
+	assert.Contains(t, s.View(), "✗ second: boom")
+}
+
+func TestSplashClusterFailure(t *testing.T) {
+	s := New(Styles{}, "first")
+	s.Update(ClusterStatusMsg{Status: StatusFailed, Err: errors.New("no cluster")})
+	assert.True(t, s.Failed())
+	assert.False(t, s.Done())
+	assert.Contains(t, s.View(), "no cluster")
+}
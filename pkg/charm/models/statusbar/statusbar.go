@@ -0,0 +1,147 @@
+package statusbar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/muesli/reflow/truncate"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// ClusterInfoMsg reports the cluster
+// the dashboard is connected to
+type ClusterInfoMsg struct {
+	Context string
+	Version string
+}
+
+type Namer interface {
+	Name() string
+}
+
+// ConnectionReporter is implemented by panels that
+// report the state of the connection to their data
+type ConnectionReporter interface {
+	ConnectionState() (helper.ConnectionState, error)
+}
+
+// ActivityTimer is implemented by panels
+// that report when they last received data
+type ActivityTimer interface {
+	LastActivity() time.Time
+}
+
+// Counter is implemented by panels that report
+// how many things they show, i.e rows or lines
+type Counter interface {
+	Count() (int, string)
+}
+
+type Styles struct {
+	Bar     lipgloss.Style
+	Warning lipgloss.Style
+	Error   lipgloss.Style
+}
+
+// Model is a tea.Model implementation that shows
+// the cluster the dashboard is connected to and
+// the status of the panel that is being viewed
+type Model struct {
+	context string
+	version string
+	panel   tea.Model
+	styles  Styles
+	width   int
+	now     func() time.Time
+}
+
+func New(styles Styles) *Model {
+	return &Model{
+		styles: styles,
+		now:    time.Now,
+	}
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case ClusterInfoMsg:
+		m.context = msg.Context
+		m.version = msg.Version
+	}
+	return m, nil
+}
+
+// SetPanel sets the panel whose status is shown
+func (m *Model) SetPanel(panel tea.Model) {
+	m.panel = panel
+}
+
+func (m *Model) View() string {
+	context := m.context
+	if context == "" {
+		context = "unknown"
+	}
+	segments := []string{fmt.Sprintf("context: %s", context)}
+	if m.version != "" {
+		segments = append(segments, fmt.Sprintf("server: %s", m.version))
+	}
+	segments = append(segments, m.panelSegments()...)
+
+	bar := strings.Join(segments, " │ ")
+	if m.width > 0 {
+		bar = truncate.StringWithTail(bar, uint(max(0, m.width-m.styles.Bar.GetHorizontalFrameSize())), "…")
+	}
+	return m.styles.Bar.Render(bar)
+}
+
+func (m *Model) panelSegments() []string {
+	if m.panel == nil {
+		return nil
+	}
+	segments := []string{}
+	if reporter, ok := m.panel.(ConnectionReporter); ok {
+		state, err := reporter.ConnectionState()
+		connection := m.renderConnection(state, err)
+		if namer, ok := m.panel.(Namer); ok {
+			connection = fmt.Sprintf("%s: %s", namer.Name(), connection)
+		}
+		segments = append(segments, connection)
+	}
+	if timer, ok := m.panel.(ActivityTimer); ok {
+		last := timer.LastActivity()
+		if last.IsZero() {
+			segments = append(segments, "no data yet")
+		} else {
+			segments = append(segments, fmt.Sprintf("updated %s ago", duration.HumanDuration(m.now().Sub(last))))
+		}
+	}
+	if counter, ok := m.panel.(Counter); ok {
+		count, unit := counter.Count()
+		segments = append(segments, fmt.Sprintf("%d %s", count, unit))
+	}
+	return segments
+}
+
+func (m *Model) renderConnection(state helper.ConnectionState, err error) string {
+	switch state {
+	case helper.ConnectionIdle:
+		return "not started"
+	case helper.ConnectionReconnecting:
+		if err != nil {
+			return m.styles.Warning.Render(fmt.Sprintf("%s (%s)", state, err))
+		}
+		return m.styles.Warning.Render(string(state))
+	case helper.ConnectionForbidden:
+		return m.styles.Error.Render(string(state))
+	default:
+		return string(state)
+	}
+}
@@ -0,0 +1,53 @@
+package statusbar
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+type panel struct {
+	helper.ConnectionTracker
+	last time.Time
+}
+
+func (p *panel) Init() tea.Cmd                           { return nil }
+func (p *panel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return p, nil }
+func (p *panel) View() string                            { return "" }
+func (p *panel) Name() string                            { return "pods" }
+func (p *panel) LastActivity() time.Time                 { return p.last }
+func (p *panel) Count() (int, string)                    { return 3, "rows" }
+
+func TestStatusBar(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := New(Styles{})
+	m.now = func() time.Time { return now }
+
+	t.Log("the context is unknown until the cluster info is received")
+	assert.Equal(t, "context: unknown", m.View())
+
+	m.Update(ClusterInfoMsg{Context: "kind-kind", Version: "v1.28.0"})
+	assert.Equal(t, "context: kind-kind │ server: v1.28.0", m.View())
+
+	t.Log("the status of the panel is shown")
+	p := &panel{}
+	m.SetPanel(p)
+	assert.Equal(t, "context: kind-kind │ server: v1.28.0 │ pods: not started │ no data yet │ 3 rows", m.View())
+
+	p.SetConnectionState(helper.ConnectionWatching, nil)
+	p.last = now.Add(-5 * time.Second)
+	assert.Equal(t, "context: kind-kind │ server: v1.28.0 │ pods: watching │ updated 5s ago │ 3 rows", m.View())
+
+	t.Log("errors are shown while reconnecting")
+	p.SetConnectionState(helper.ConnectionReconnecting, errors.New("connection refused"))
+	assert.Contains(t, m.View(), "pods: reconnecting (connection refused)")
+
+	t.Log("the bar is truncated to the width")
+	m.Update(tea.WindowSizeMsg{Width: 20})
+	assert.Equal(t, 20, lipgloss.Width(m.View()))
+}
@@ -0,0 +1,159 @@
+package tabs
+
+import (
+	"github.com/charmbracelet/bubbles/help"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+)
+
+// Stepper is implemented by tab models that have their own
+// set of tabs that can be stepped through using the same
+// keys that are used to change tabs
+type Stepper interface {
+	// Next selects the next tab, returning false
+	// without changing tabs if the last tab is selected
+	Next() bool
+	// Prev selects the previous tab, returning false
+	// without changing tabs if the first tab is selected
+	Prev() bool
+	SelectFirst()
+	SelectLast()
+}
+
+// Group is a tea.Model implementation that shows a section
+// of the dashboard as a second row of tabs. It remembers its
+// selected tab while other sections are being viewed.
+type Group struct {
+	name    string
+	tabber  *TabModel
+	visible bool
+}
+
+func NewGroup(name string, keyMap TabberKeyMap, styles TabModelStyleOptions, tabs ...Tab) *Group {
+	return &Group{
+		name:   name,
+		tabber: New(keyMap, styles, tabs...),
+	}
+}
+
+func (g *Group) Init() tea.Cmd { return nil }
+
+func (g *Group) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	g.tabber, cmd = g.tabber.Update(msg)
+	return g, cmd
+}
+
+func (g *Group) View() string {
+	if len(g.tabber.tabs) == 0 {
+		return ""
+	}
+	return g.tabber.View()
+}
+
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Panels returns the models of every tab in the group
+func (g *Group) Panels() []tea.Model {
+	panels := []tea.Model{}
+	for _, tab := range g.tabber.tabs {
+		panels = append(panels, tab.Model)
+	}
+	return panels
+}
+
+func (g *Group) Help() help.KeyMap {
+	if len(g.tabber.tabs) == 0 {
+		return helper.NewCompositeHelpKeyMap()
+	}
+	return g.tabber.Help()
+}
+
+// Show notifies the selected tab of the group that it is shown
+func (g *Group) Show() {
+	g.visible = true
+	if len(g.tabber.tabs) > 0 {
+		show(g.tabber.tabs[g.tabber.selected].Model)
+	}
+}
+
+// Hide notifies the selected tab of the group that it is hidden
+func (g *Group) Hide() {
+	g.visible = false
+	if len(g.tabber.tabs) > 0 {
+		hide(g.tabber.tabs[g.tabber.selected].Model)
+	}
+}
+
+func (g *Group) Next() bool {
+	if g.tabber.selected >= len(g.tabber.tabs)-1 {
+		return false
+	}
+	g.selectTab(g.tabber.selected + 1)
+	return true
+}
+
+func (g *Group) Prev() bool {
+	if g.tabber.selected <= 0 {
+		return false
+	}
+	g.selectTab(g.tabber.selected - 1)
+	return true
+}
+
+func (g *Group) SelectFirst() {
+	g.selectTab(0)
+}
+
+func (g *Group) SelectLast() {
+	g.selectTab(max(0, len(g.tabber.tabs)-1))
+}
+
+// selectTab changes the selected tab, only notifying
+// the tabs of the change while the group is visible
+func (g *Group) selectTab(selected int) {
+	if !g.visible {
+		g.tabber.selected = selected
+		return
+	}
+	g.tabber.selectTab(selected)
+}
+
+// Focused returns the panel of the selected tab
+func (g *Group) Focused() tea.Model {
+	return g.tabber.Focused()
+}
+
+func (g *Group) CapturingInput() bool {
+	return g.tabber.CapturingInput()
+}
+
+// Activity returns the combined activity of every tab in the group
+func (g *Group) Activity() (int, helper.Attention) {
+	return combinedActivity(g.Panels()...)
+}
+
+// Seen resets the activity of the selected
+// tab since it is the one that was looked at
+func (g *Group) Seen() {
+	if len(g.tabber.tabs) > 0 {
+		seen(g.tabber.tabs[g.tabber.selected].Model)
+	}
+}
+
+// combinedActivity returns the total count and
+// highest attention level of the activity of models
+func combinedActivity(models ...tea.Model) (int, helper.Attention) {
+	total := 0
+	attention := helper.AttentionNone
+	for _, model := range models {
+		if reporter, ok := model.(ActivityReporter); ok {
+			count, level := reporter.Activity()
+			total += count
+			attention = max(attention, level)
+		}
+	}
+	return total, attention
+}
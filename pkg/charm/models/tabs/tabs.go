@@ -1,6 +1,7 @@
 package tabs
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -14,6 +15,33 @@ type Helper interface {
 	Help() help.KeyMap
 }
 
+// Visibility is implemented by models that need to
+// know when their tab is shown or hidden
+type Visibility interface {
+	Show()
+	Hide()
+}
+
+// ActivityReporter is implemented by models that
+// can report new data since they were last seen
+type ActivityReporter interface {
+	Activity() (int, helper.Attention)
+	Seen()
+}
+
+// Focuser is implemented by models made up of multiple
+// panels to return the panel that is being interacted with
+type Focuser interface {
+	Focused() tea.Model
+}
+
+// InputCapturer is implemented by models that can
+// capture text input that should not be treated as
+// a key press for the dashboard
+type InputCapturer interface {
+	CapturingInput() bool
+}
+
 type Tab struct {
 	Name  string
 	Model tea.Model
@@ -28,6 +56,12 @@ type TabModelStyleOptions struct {
 	TabStyle      lipgloss.Style
 	LeftArrow     string
 	RightArrow    string
+	// ActivityStyle is used for the marker of tabs with new activity.
+	// WarningStyle and ErrorStyle are used for the names of tabs
+	// with new activity that needs attention.
+	ActivityStyle lipgloss.Style
+	WarningStyle  lipgloss.Style
+	ErrorStyle    lipgloss.Style
 }
 
 type TabModel struct {
@@ -35,6 +69,7 @@ type TabModel struct {
 	selected int
 	keyMap   TabberKeyMap
 	width    int
+	height   int
 	styles   TabModelStyleOptions
 	pager    *pager
 }
@@ -55,6 +90,9 @@ func New(keyMap TabberKeyMap, styles TabModelStyleOptions, tabs ...Tab) *TabMode
 }
 
 func (t *TabModel) Init() tea.Cmd {
+	if len(t.tabs) > 0 {
+		show(t.tabs[t.selected].Model)
+	}
 	return nil
 }
 
@@ -63,20 +101,24 @@ func (t *TabModel) Update(msg tea.Msg) (*TabModel, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, t.keyMap.TabRight):
-			t.selected++
-			if t.selected > len(t.tabs)-1 {
-				t.selected = 0
-			}
+			t.NextTab()
 			return t, nil
 		case key.Matches(msg, t.keyMap.TabLeft):
-			t.selected--
-			if t.selected < 0 {
-				t.selected = len(t.tabs) - 1
-			}
+			t.PrevTab()
+			return t, nil
+		case key.Matches(msg, t.keyMap.SectionRight):
+			t.NextSection()
+			return t, nil
+		case key.Matches(msg, t.keyMap.SectionLeft):
+			t.PrevSection()
 			return t, nil
 		}
 	case tea.WindowSizeMsg:
 		t.width = msg.Width
+		t.height = msg.Height
+		return t, t.resize()
+	case helper.ErrorMsg:
+		// errors are for panels that may not be selected
 		var cmd tea.Cmd
 		for i := range t.tabs {
 			var tempCmd tea.Cmd
@@ -84,6 +126,20 @@ func (t *TabModel) Update(msg tea.Msg) (*TabModel, tea.Cmd) {
 			cmd = tea.Batch(cmd, tempCmd)
 		}
 		return t, cmd
+	case tea.MouseMsg:
+		barHeight := lipgloss.Height(t.renderTabBar())
+		if msg.Y < barHeight {
+			if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+				t.clickTabBar(msg.X)
+			}
+			return t, nil
+		}
+		// mouse events are sent to the selected tab
+		// relative to the top left of its content
+		x, y := helper.FrameOffset(t.styles.ContentStyle)
+		var cmd tea.Cmd
+		t.tabs[t.selected].Model, cmd = t.tabs[t.selected].Model.Update(helper.TranslateMouse(msg, x, barHeight+y))
+		return t, cmd
 	}
 
 	var cmd tea.Cmd
@@ -91,15 +147,261 @@ func (t *TabModel) Update(msg tea.Msg) (*TabModel, tea.Cmd) {
 	return t, cmd
 }
 
+// NextTab selects the tab to the right. Tabs with their own set
+// of tabs are stepped through before moving on to the next tab.
+func (t *TabModel) NextTab() {
+	if stepper, ok := t.tabs[t.selected].Model.(Stepper); ok && stepper.Next() {
+		return
+	}
+	selected := t.next()
+	if stepper, ok := t.tabs[selected].Model.(Stepper); ok {
+		stepper.SelectFirst()
+	}
+	t.selectTab(selected)
+}
+
+// PrevTab selects the tab to the left. Tabs with their own set of
+// tabs are stepped through before moving on to the previous tab.
+func (t *TabModel) PrevTab() {
+	if stepper, ok := t.tabs[t.selected].Model.(Stepper); ok && stepper.Prev() {
+		return
+	}
+	selected := t.prev()
+	if stepper, ok := t.tabs[selected].Model.(Stepper); ok {
+		stepper.SelectLast()
+	}
+	t.selectTab(selected)
+}
+
+// NextSection selects the tab to the right without
+// stepping through the tabs of the selected tab
+func (t *TabModel) NextSection() {
+	t.selectTab(t.next())
+}
+
+// PrevSection selects the tab to the left without
+// stepping through the tabs of the selected tab
+func (t *TabModel) PrevSection() {
+	t.selectTab(t.prev())
+}
+
+// Target is a tab that can be selected directly
+type Target struct {
+	Name  string
+	index []int
+}
+
+// Targets returns every tab that can be selected,
+// including the tabs of any sections
+func (t *TabModel) Targets() []Target {
+	targets := []Target{}
+	for i, tab := range t.tabs {
+		targets = append(targets, Target{Name: tab.Name, index: []int{i}})
+		if group, ok := tab.Model.(*Group); ok {
+			for j, child := range group.tabber.tabs {
+				targets = append(targets, Target{Name: tab.Name + " / " + child.Name, index: []int{i, j}})
+			}
+		}
+	}
+	return targets
+}
+
+// SelectTarget selects the tab for the target
+func (t *TabModel) SelectTarget(target Target) {
+	if len(target.index) == 0 || target.index[0] >= len(t.tabs) {
+		return
+	}
+	if group, ok := t.tabs[target.index[0]].Model.(*Group); ok && len(target.index) > 1 {
+		group.selectTab(target.index[1])
+	}
+	t.selectTab(target.index[0])
+}
+
+// SelectedTarget returns the target for the selected
+// tab, including the selected tab of a section
+func (t *TabModel) SelectedTarget() (Target, bool) {
+	if len(t.tabs) == 0 {
+		return Target{}, false
+	}
+	tab := t.tabs[t.selected]
+	if group, ok := tab.Model.(*Group); ok && len(group.tabber.tabs) > 0 {
+		child := group.tabber.tabs[group.tabber.selected]
+		return Target{Name: tab.Name + " / " + child.Name, index: []int{t.selected, group.tabber.selected}}, true
+	}
+	return Target{Name: tab.Name, index: []int{t.selected}}, true
+}
+
+// Selected returns the index of the selected tab
+func (t *TabModel) Selected() int {
+	return t.selected
+}
+
+// Select selects the tab at index, ignoring indexes out of range
+func (t *TabModel) Select(index int) {
+	if index < 0 || index >= len(t.tabs) {
+		return
+	}
+	t.selectTab(index)
+}
+
+// Focused returns the panel that is being viewed, looking
+// into the selected tab if it is made up of multiple panels
+func (t *TabModel) Focused() tea.Model {
+	if len(t.tabs) == 0 {
+		return nil
+	}
+	model := t.tabs[t.selected].Model
+	if focuser, ok := model.(Focuser); ok {
+		return focuser.Focused()
+	}
+	return model
+}
+
+// CapturingInput returns whether or not the selected tab
+// is currently capturing text input, i.e for a search
+func (t *TabModel) CapturingInput() bool {
+	if len(t.tabs) == 0 {
+		return false
+	}
+	if capturer, ok := t.tabs[t.selected].Model.(InputCapturer); ok {
+		return capturer.CapturingInput()
+	}
+	return false
+}
+
+// clickTabBar selects the tab that was clicked at x. Clicking
+// the pager arrows selects the first tab of the next or previous page.
+func (t *TabModel) clickTabBar(x int) {
+	t.pager.setPages(t.labels(), t.selected, t.width)
+	selected, ok := t.pager.tabAt(t.selected, x)
+	if !ok {
+		return
+	}
+	if selected < 0 {
+		selected = len(t.tabs) - 1
+	}
+	if selected >= len(t.tabs) {
+		selected = 0
+	}
+	t.selectTab(selected)
+}
+
+func (t *TabModel) next() int {
+	selected := t.selected + 1
+	if selected > len(t.tabs)-1 {
+		selected = 0
+	}
+	return selected
+}
+
+func (t *TabModel) prev() int {
+	selected := t.selected - 1
+	if selected < 0 {
+		selected = len(t.tabs) - 1
+	}
+	return selected
+}
+
+// resize sends every tab a tea.WindowSizeMsg with the size
+// of the content area, which is what remains of the tab
+// model's size once the tab bar has been rendered
+func (t *TabModel) resize() tea.Cmd {
+	size := t.contentSize()
+	var cmd tea.Cmd
+	for i := range t.tabs {
+		var tempCmd tea.Cmd
+		t.tabs[i].Model, tempCmd = t.tabs[i].Model.Update(size)
+		cmd = tea.Batch(cmd, tempCmd)
+	}
+	return cmd
+}
+
+func (t *TabModel) contentSize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{
+		Width:  max(0, t.width-t.styles.ContentStyle.GetHorizontalFrameSize()),
+		Height: max(0, t.height-lipgloss.Height(t.renderTabBar())-t.styles.ContentStyle.GetVerticalFrameSize()),
+	}
+}
+
+// selectTab changes the selected tab, notifying the
+// models of the previous and new tab of the change
+func (t *TabModel) selectTab(selected int) {
+	if selected == t.selected {
+		return
+	}
+	hide(t.tabs[t.selected].Model)
+	seen(t.tabs[t.selected].Model)
+	t.selected = selected
+	show(t.tabs[t.selected].Model)
+	seen(t.tabs[t.selected].Model)
+}
+
+func seen(model tea.Model) {
+	if reporter, ok := model.(ActivityReporter); ok {
+		reporter.Seen()
+	}
+}
+
+// label returns the name of the tab at index with a marker
+// for any activity since the tab was last seen. The activity
+// of the selected tab is not shown since it is being looked at.
+func (t *TabModel) label(index int) string {
+	tab := t.tabs[index]
+	reporter, ok := tab.Model.(ActivityReporter)
+	if !ok || index == t.selected {
+		return tab.Name
+	}
+	count, attention := reporter.Activity()
+	if count == 0 {
+		return tab.Name
+	}
+
+	name := tab.Name
+	switch attention {
+	case helper.AttentionWarning:
+		name = t.styles.WarningStyle.Render(name)
+	case helper.AttentionError:
+		name = t.styles.ErrorStyle.Render(name)
+	}
+	marker := fmt.Sprintf("●%d", count)
+	if count > 99 {
+		marker = "●99+"
+	}
+	return name + " " + t.styles.ActivityStyle.Render(marker)
+}
+
+func show(model tea.Model) {
+	if v, ok := model.(Visibility); ok {
+		v.Show()
+	}
+}
+
+func hide(model tea.Model) {
+	if v, ok := model.(Visibility); ok {
+		v.Hide()
+	}
+}
+
 func (t *TabModel) View() string {
-	t.pager.setPages(t.tabs, t.selected, t.width)
+	content := t.styles.ContentStyle.Render(t.tabs[t.selected].Model.View())
+	return lipgloss.JoinVertical(0, t.renderTabBar(), content)
+}
+
+func (t *TabModel) labels() []string {
+	labels := []string{}
+	for i := range t.tabs {
+		labels = append(labels, t.label(i))
+	}
+	return labels
+}
+
+func (t *TabModel) renderTabBar() string {
+	t.pager.setPages(t.labels(), t.selected, t.width)
 	tabBlock := t.pager.renderForSelectedTab(t.selected)
 	// gap is a repeating of the spaces so that the bottom border continues the entire width
 	// of the terminal. This allows it to look like a proper set of tabs
 	gap := t.styles.GapStyle.Render(strings.Repeat(" ", max(0, t.width-lipgloss.Width(tabBlock)-2)))
-	tabsWithBorder := lipgloss.JoinHorizontal(lipgloss.Bottom, tabBlock, gap)
-	content := t.styles.ContentStyle.Render(t.tabs[t.selected].Model.View())
-	return lipgloss.JoinVertical(0, tabsWithBorder, content)
+	return lipgloss.JoinHorizontal(lipgloss.Bottom, tabBlock, gap)
 }
 
 func (t *TabModel) Help() help.KeyMap {
@@ -112,8 +414,10 @@ func (t *TabModel) Help() help.KeyMap {
 }
 
 type TabberKeyMap struct {
-	TabRight key.Binding
-	TabLeft  key.Binding
+	TabRight     key.Binding
+	TabLeft      key.Binding
+	SectionRight key.Binding
+	SectionLeft  key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
@@ -126,7 +430,7 @@ func (k TabberKeyMap) ShortHelp() []key.Binding {
 // key.Map interface.
 func (k TabberKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.TabLeft, k.TabRight},
+		{k.TabLeft, k.TabRight, k.SectionLeft, k.SectionRight},
 	}
 }
 
@@ -139,6 +443,14 @@ var DefaultTabberKeys = TabberKeyMap{
 		key.WithKeys("shift+tab"),
 		key.WithHelp("shift+tab", "change tabs to the left"),
 	),
+	SectionRight: key.NewBinding(
+		key.WithKeys("alt+right"),
+		key.WithHelp("alt+→", "change sections to the right"),
+	),
+	SectionLeft: key.NewBinding(
+		key.WithKeys("alt+left"),
+		key.WithHelp("alt+←", "change sections to the left"),
+	),
 }
 
 type page struct {
@@ -156,12 +468,7 @@ type pager struct {
 }
 
 func (p *pager) renderForSelectedTab(selected int) string {
-	tabPage := page{}
-	for _, page := range p.pages {
-		if page.start <= selected && page.end >= selected {
-			tabPage = page
-		}
-	}
+	tabPage, _ := p.pageFor(selected)
 
 	tabBlock := lipgloss.JoinHorizontal(lipgloss.Top, tabPage.tabs...)
 	if len(p.pages) > 1 {
@@ -171,14 +478,54 @@ func (p *pager) renderForSelectedTab(selected int) string {
 	return tabBlock
 }
 
-func (p *pager) setPages(tabs []Tab, selected int, width int) {
+// tabAt returns the index of the tab rendered at x for the page of
+// the selected tab. Clicking the arrows returns the index of the tab
+// just outside of the page, which may be out of range.
+func (p *pager) tabAt(selected int, x int) (int, bool) {
+	tabPage, ok := p.pageFor(selected)
+	if !ok {
+		return 0, false
+	}
+
+	offset := 0
+	if len(p.pages) > 1 {
+		offset = lipgloss.Width(p.tabLeftArrow)
+		if x < offset {
+			return tabPage.start - 1, true
+		}
+	}
+	for i, tab := range tabPage.tabs {
+		offset += lipgloss.Width(tab)
+		if x < offset {
+			return tabPage.start + i, true
+		}
+	}
+	if len(p.pages) > 1 && x < offset+lipgloss.Width(p.tabRightArrow) {
+		return tabPage.start + len(tabPage.tabs), true
+	}
+	return 0, false
+}
+
+func (p *pager) pageFor(selected int) (page, bool) {
+	tabPage := page{}
+	found := false
+	for _, page := range p.pages {
+		if page.start <= selected && page.end >= selected {
+			tabPage = page
+			found = true
+		}
+	}
+	return tabPage, found
+}
+
+func (p *pager) setPages(labels []string, selected int, width int) {
 	tabPages := []page{}
 	tempTab := ""
 	tempPage := page{start: 0, tabs: []string{}}
-	for i, tab := range tabs {
-		renderedTab := p.tabStyle.Render(tab.Name)
+	for i, label := range labels {
+		renderedTab := p.tabStyle.Render(label)
 		if i == selected {
-			renderedTab = p.selectedStyle.Render(tab.Name)
+			renderedTab = p.selectedStyle.Render(label)
 		}
 		tempTab = lipgloss.JoinHorizontal(lipgloss.Top, tempTab, renderedTab)
 		joined := lipgloss.JoinHorizontal(lipgloss.Bottom, p.tabLeftArrow, tempTab, p.tabRightArrow)
@@ -192,7 +539,7 @@ func (p *pager) setPages(tabs []Tab, selected int, width int) {
 		tempPage.tabs = append(tempPage.tabs, renderedTab)
 	}
 	if tempTab != "" {
-		tempPage.end = len(tabs) - 1
+		tempPage.end = len(labels) - 1
 		tabPages = append(tabPages, tempPage)
 	}
 	p.pages = tabPages
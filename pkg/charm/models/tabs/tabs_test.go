@@ -1,9 +1,12 @@
 package tabs
 
 import (
+	"errors"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,3 +29,179 @@ func TestTabberUpdate(t *testing.T) {
 	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
 	assert.Equal(t, 0, tabber.selected)
 }
+
+type visibilityModel struct {
+	shown  int
+	hidden int
+}
+
+func (v *visibilityModel) Init() tea.Cmd                           { return nil }
+func (v *visibilityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return v, nil }
+func (v *visibilityModel) View() string                            { return "" }
+func (v *visibilityModel) Show()                                   { v.shown++ }
+func (v *visibilityModel) Hide()                                   { v.hidden++ }
+
+func TestTabberVisibility(t *testing.T) {
+	first := &visibilityModel{}
+	second := &visibilityModel{}
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "first", Model: first}, Tab{Name: "second", Model: second})
+
+	t.Log("initial tab is shown on init")
+	tabber.Init()
+	assert.Equal(t, 1, first.shown)
+	assert.Equal(t, 0, second.shown)
+
+	t.Log("changing tabs hides the previous tab and shows the new tab")
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 1, first.hidden)
+	assert.Equal(t, 1, second.shown)
+
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 2, first.shown)
+	assert.Equal(t, 1, second.hidden)
+}
+
+type sizeModel struct {
+	size tea.WindowSizeMsg
+}
+
+func (s *sizeModel) Init() tea.Cmd { return nil }
+func (s *sizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		s.size = size
+	}
+	return s, nil
+}
+func (s *sizeModel) View() string { return "" }
+
+func TestTabberResize(t *testing.T) {
+	model := &sizeModel{}
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{
+		TabStyle:      lipgloss.NewStyle().Border(lipgloss.RoundedBorder()),
+		SelectedStyle: lipgloss.NewStyle().Border(lipgloss.RoundedBorder()),
+	}, Tab{Name: "test", Model: model})
+
+	t.Log("tabs are sent the size left over after the tab bar")
+	tabber.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+	assert.Equal(t, tea.WindowSizeMsg{Width: 100, Height: 47}, model.size)
+}
+
+type errorModel struct {
+	err error
+}
+
+func (e *errorModel) Init() tea.Cmd { return nil }
+func (e *errorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if err, ok := helper.ErrorFor(msg, e); ok {
+		e.err = err
+	}
+	return e, nil
+}
+func (e *errorModel) View() string { return "" }
+
+func TestTabberErrors(t *testing.T) {
+	first := &errorModel{}
+	a := &errorModel{}
+	b := &errorModel{}
+	group := NewGroup("section", DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "a", Model: a}, Tab{Name: "b", Model: b})
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "first", Model: first}, Tab{Name: "section", Model: group})
+
+	t.Log("errors reach the panel they are for even if it isn't selected")
+	err := errors.New("some error")
+	tabber.Update(helper.ErrorMsg{Panel: b, Err: err})
+	assert.Equal(t, err, b.err)
+	assert.Nil(t, first.err)
+	assert.Nil(t, a.err)
+}
+
+func TestTabberGroups(t *testing.T) {
+	first := &visibilityModel{}
+	a := &visibilityModel{}
+	b := &visibilityModel{}
+	last := &visibilityModel{}
+	group := NewGroup("section", DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "a", Model: a}, Tab{Name: "b", Model: b})
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{},
+		Tab{Name: "first", Model: first},
+		Tab{Name: "section", Model: group},
+		Tab{Name: "last", Model: last},
+	)
+	tabber.Init()
+
+	t.Log("changing tabs steps through the tabs of a section")
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 1, tabber.selected)
+	assert.Equal(t, 1, a.shown)
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 1, tabber.selected)
+	assert.Equal(t, 1, a.hidden)
+	assert.Equal(t, 1, b.shown)
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	assert.Equal(t, 2, tabber.selected)
+	assert.Equal(t, 1, b.hidden)
+
+	t.Log("changing tabs to the left enters a section at its last tab")
+	tabber.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	assert.Equal(t, 1, tabber.selected)
+	assert.Equal(t, 2, b.shown)
+	assert.Equal(t, 1, a.shown)
+
+	t.Log("changing sections keeps the selected tab of a section")
+	tabber.Update(tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	assert.Equal(t, 0, tabber.selected)
+	assert.Equal(t, 2, b.hidden)
+	tabber.Update(tea.KeyMsg{Type: tea.KeyRight, Alt: true})
+	assert.Equal(t, 1, tabber.selected)
+	assert.Equal(t, 3, b.shown)
+	assert.Equal(t, 1, a.shown)
+
+	assert.Equal(t, []tea.Model{a, b}, group.Panels())
+}
+
+func TestTabberMouse(t *testing.T) {
+	first := &sizeModel{}
+	second := &sizeModel{}
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "first", Model: first}, Tab{Name: "second", Model: second})
+	tabber.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	t.Log("clicking a tab selects it")
+	tabber.Update(tea.MouseMsg{X: 7, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 1, tabber.selected)
+	tabber.Update(tea.MouseMsg{X: 2, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 0, tabber.selected)
+
+	t.Log("clicking past the tabs does nothing")
+	tabber.Update(tea.MouseMsg{X: 50, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	assert.Equal(t, 0, tabber.selected)
+}
+
+type activityModel struct {
+	helper.ActivityTracker
+}
+
+func (a *activityModel) Init() tea.Cmd                           { return nil }
+func (a *activityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return a, nil }
+func (a *activityModel) View() string                            { return "" }
+
+func TestTabberActivity(t *testing.T) {
+	first := &activityModel{}
+	second := &activityModel{}
+	tabber := New(DefaultTabberKeys, TabModelStyleOptions{}, Tab{Name: "first", Model: first}, Tab{Name: "second", Model: second})
+	tabber.Update(tea.WindowSizeMsg{Width: 100, Height: 50})
+
+	t.Log("activity on the selected tab is not shown")
+	first.RecordActivity(helper.AttentionNone)
+	assert.Equal(t, "first", tabber.label(0))
+
+	t.Log("activity on a background tab is shown")
+	second.RecordActivity(helper.AttentionNone)
+	second.RecordActivity(helper.AttentionError)
+	assert.Equal(t, "second ●2", tabber.label(1))
+
+	t.Log("selecting a tab marks it and the previous tab as seen")
+	tabber.Update(tea.KeyMsg{Type: tea.KeyTab})
+	count, _ := first.Activity()
+	assert.Equal(t, 0, count)
+	count, _ = second.Activity()
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "first", tabber.label(0))
+}
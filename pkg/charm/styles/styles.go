@@ -30,12 +30,29 @@ type Theme struct {
 	TabLeftArrow string
 	// TabRightArrow is the string to render to indicate there are more tabs to the right
 	TabRightArrow string
+
+	// Palette is the set of named colors that can be
+	// used by panels, i.e in the style rules of a table
+	Palette map[string]lipgloss.AdaptiveColor
 }
 
 const DefaultThemePath = "~/.config/buoy/themes/default.json"
 
 var DefaultColor = lipgloss.AdaptiveColor{Light: "63", Dark: "117"}
 
+// DefaultPalette is the palette used for any colors
+// that aren't named in the palette of a theme
+var DefaultPalette = map[string]lipgloss.AdaptiveColor{
+	"red":     {Light: "160", Dark: "203"},
+	"orange":  {Light: "166", Dark: "215"},
+	"yellow":  {Light: "172", Dark: "221"},
+	"green":   {Light: "28", Dark: "114"},
+	"cyan":    {Light: "30", Dark: "87"},
+	"blue":    {Light: "26", Dark: "75"},
+	"magenta": {Light: "127", Dark: "213"},
+	"gray":    {Light: "243", Dark: "247"},
+}
+
 func LoadTheme(themePath string) (Theme, error) {
 	t := Theme{
 		TabColor:                  DefaultColor,
@@ -45,6 +62,7 @@ func LoadTheme(themePath string) (Theme, error) {
 		SyntaxHighlightLightTheme: "monokailight",
 		TabRightArrow:             " > ",
 		TabLeftArrow:              " < ",
+		Palette:                   DefaultPalette,
 	}
 	// If the specified theme file doesn't exist, use the default theme
 	if _, err := os.Stat(themePath); err != nil {
@@ -62,6 +80,15 @@ func LoadTheme(themePath string) (Theme, error) {
 		return t, fmt.Errorf("unmarshalling theme: %w", err)
 	}
 
+	palette := map[string]lipgloss.AdaptiveColor{}
+	for name, color := range DefaultPalette {
+		palette[name] = color
+	}
+	for name, color := range customTheme.Palette {
+		palette[name] = color
+	}
+	customTheme.Palette = palette
+
 	return *customTheme, nil
 }
 
@@ -93,6 +120,10 @@ func (t *Theme) TableSelectedRowStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(t.SelectedRowHighlightColor)
 }
 
+func (t *Theme) TableFilterStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Italic(true).Faint(true)
+}
+
 func (t *Theme) LogSearchHighlightStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(t.LogSearchHighlightColor)
 }
@@ -101,6 +132,50 @@ func (t *Theme) LogSearchModeStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Italic(true).Faint(true)
 }
 
+func (t *Theme) PaneStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.AdaptiveColor{Light: "250", Dark: "240"})
+}
+
+func (t *Theme) FocusedPaneStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.TabColor)
+}
+
+func (t *Theme) SplashLogoStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, true).BorderForeground(t.TabColor)
+}
+
+func (t *Theme) ErrorStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "160", Dark: "203"})
+}
+
+func (t *Theme) PaletteStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.TabColor).Padding(0, 1)
+}
+
+func (t *Theme) PaletteMatchStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.TabColor).Bold(true)
+}
+
+func (t *Theme) PaletteBindingStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Faint(true)
+}
+
+func (t *Theme) ActivityStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.TabColor)
+}
+
+func (t *Theme) WarningStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "172", Dark: "221"})
+}
+
+func (t *Theme) HelpKeyStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true)
+}
+
+func (t *Theme) StatusBarStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Faint(true)
+}
+
 func (t *Theme) TabArrowRight() string {
 	return t.TabRightArrow
 }
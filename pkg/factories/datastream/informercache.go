@@ -0,0 +1,481 @@
+package datastream
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	defaultResyncPeriod = 1 * time.Minute
+	// healthCheckPeriod is how often an informer that had a
+	// watch error is checked for having reconnected
+	healthCheckPeriod = 5 * time.Second
+)
+
+// ConnectionStateSetter is implemented by panels that
+// show the state of the connection to their data
+type ConnectionStateSetter interface {
+	SetConnectionState(helper.ConnectionState, error)
+}
+
+// informerRequest describes the set of objects a panel
+// wants to receive events for
+type informerRequest struct {
+	gvr           schema.GroupVersionResource
+	namespace     string
+	labelSelector labels.Selector
+	fieldSelector fields.Selector
+	name          string
+	// metadataOnly signals that only the metadata
+	// of the objects is needed
+	metadataOnly bool
+	// prune is a list of dot notation paths to fields
+	// that can be removed from the objects before caching
+	prune []string
+}
+
+func (r informerRequest) key() informerKey {
+	key := informerKey{
+		gvr:          r.gvr,
+		namespace:    r.namespace,
+		name:         r.name,
+		metadataOnly: r.metadataOnly,
+	}
+	if r.labelSelector != nil {
+		key.labelSelector = r.labelSelector.String()
+	}
+	if r.fieldSelector != nil {
+		key.fieldSelector = r.fieldSelector.String()
+	}
+	prune := append([]string{}, r.prune...)
+	sort.Strings(prune)
+	key.prune = strings.Join(prune, ",")
+	return key
+}
+
+// matches returns whether or not the object is part of
+// the set of objects described by the request
+func (r informerRequest) matches(u *unstructured.Unstructured) bool {
+	if r.namespace != "" && u.GetNamespace() != r.namespace {
+		return false
+	}
+	if r.name != "" && u.GetName() != r.name {
+		return false
+	}
+	if r.labelSelector != nil && !r.labelSelector.Matches(labels.Set(u.GetLabels())) {
+		return false
+	}
+	return true
+}
+
+// informerKey uniquely identifies an informer in the informerCache
+type informerKey struct {
+	gvr           schema.GroupVersionResource
+	namespace     string
+	labelSelector string
+	fieldSelector string
+	name          string
+	metadataOnly  bool
+	prune         string
+}
+
+// covers returns whether an informer created for this key
+// watches every object that an informer for the other key would
+func (k informerKey) covers(other informerKey) bool {
+	if k.gvr != other.gvr {
+		return false
+	}
+	if k.namespace != "" && k.namespace != other.namespace {
+		return false
+	}
+	if k.labelSelector != "" && k.labelSelector != other.labelSelector {
+		return false
+	}
+	// field selectors are only evaluated by the API server
+	// since cached objects may not have the selected fields
+	if k.fieldSelector != other.fieldSelector {
+		return false
+	}
+	if k.name != "" && k.name != other.name {
+		return false
+	}
+	if k.metadataOnly && !other.metadataOnly {
+		return false
+	}
+	return k.prunesSubsetOf(other)
+}
+
+// narrowerThan returns whether this key watches fewer objects
+// than the other key, ordering keys that are as narrow by their
+// fields so that the order is the same on every run
+func (k informerKey) narrowerThan(other informerKey) bool {
+	if k.restrictions() != other.restrictions() {
+		return k.restrictions() > other.restrictions()
+	}
+	return fmt.Sprintf("%+v", k) < fmt.Sprintf("%+v", other)
+}
+
+// restrictions counts the ways the key limits the objects
+// that are watched and the fields that are cached
+func (k informerKey) restrictions() int {
+	count := 0
+	for _, restricted := range []bool{k.namespace != "", k.labelSelector != "", k.fieldSelector != "", k.name != "", k.metadataOnly} {
+		if restricted {
+			count++
+		}
+	}
+	if k.prune != "" {
+		count += len(strings.Split(k.prune, ","))
+	}
+	return count
+}
+
+// prunesSubsetOf returns whether every field pruned by this key
+// is also pruned by, or not needed by, the other key
+func (k informerKey) prunesSubsetOf(other informerKey) bool {
+	if k.prune == "" {
+		return true
+	}
+	otherPrune := map[string]struct{}{}
+	for _, path := range strings.Split(other.prune, ",") {
+		otherPrune[path] = struct{}{}
+	}
+	for _, path := range strings.Split(k.prune, ",") {
+		if _, ok := otherPrune[path]; ok {
+			continue
+		}
+		if other.metadataOnly && !isMetadataPath(path) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+var _ Datastream = &sharedInformer{}
+
+// sharedInformer is an informer that can be shared
+// by multiple panels. It is only ever started once,
+// regardless of how many panels call Run
+type sharedInformer struct {
+	informer cache.SharedIndexInformer
+	mutex    *sync.Mutex
+	started  bool
+	state    helper.ConnectionState
+	err      error
+	// errorRV is the last synced resource version
+	// at the time of the last watch error
+	errorRV   string
+	listeners []ConnectionStateSetter
+}
+
+func newSharedInformer(informer cache.SharedIndexInformer) (*sharedInformer, error) {
+	inf := &sharedInformer{
+		informer: informer,
+		mutex:    &sync.Mutex{},
+	}
+	if err := informer.SetWatchErrorHandler(inf.watchErrorHandler); err != nil {
+		return nil, fmt.Errorf("setting informer watch error handler: %w", err)
+	}
+	return inf, nil
+}
+
+func (s *sharedInformer) Run(stopCh <-chan struct{}) {
+	s.mutex.Lock()
+	if s.started {
+		s.mutex.Unlock()
+		return
+	}
+	s.started = true
+	s.mutex.Unlock()
+	s.setState(helper.ConnectionSyncing, nil)
+	go s.checkHealth(stopCh)
+	s.informer.Run(stopCh)
+}
+
+// addListener registers a panel to be notified of changes
+// to the connection state, starting with the current state
+func (s *sharedInformer) addListener(listener ConnectionStateSetter) {
+	s.mutex.Lock()
+	s.listeners = append(s.listeners, listener)
+	state, err := s.state, s.err
+	s.mutex.Unlock()
+	listener.SetConnectionState(state, err)
+}
+
+func (s *sharedInformer) setState(state helper.ConnectionState, err error) {
+	s.mutex.Lock()
+	s.state = state
+	s.err = err
+	listeners := append([]ConnectionStateSetter{}, s.listeners...)
+	s.mutex.Unlock()
+	for _, listener := range listeners {
+		listener.SetConnectionState(state, err)
+	}
+}
+
+// watchErrorHandler reports watch errors to the listeners
+// instead of logging them since logs would draw over the UI
+func (s *sharedInformer) watchErrorHandler(_ *cache.Reflector, err error) {
+	state := helper.ConnectionReconnecting
+	if apierrors.IsForbidden(err) {
+		state = helper.ConnectionForbidden
+	}
+	s.mutex.Lock()
+	s.errorRV = s.informer.LastSyncResourceVersion()
+	s.mutex.Unlock()
+	s.setState(state, err)
+}
+
+// checkHealth marks the informer as watching once it has synced
+// and then periodically checks if it recovered from watch errors
+func (s *sharedInformer) checkHealth(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, s.informer.HasSynced) {
+		return
+	}
+	s.recover()
+	ticker := time.NewTicker(healthCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.recover()
+		}
+	}
+}
+
+// recover marks the informer as watching if it is syncing, or if
+// it has listed or watched successfully since the last watch error
+func (s *sharedInformer) recover() {
+	s.mutex.Lock()
+	recovered := false
+	switch s.state {
+	case helper.ConnectionSyncing:
+		recovered = true
+	case helper.ConnectionReconnecting, helper.ConnectionForbidden:
+		recovered = s.informer.LastSyncResourceVersion() != s.errorRV
+	}
+	s.mutex.Unlock()
+	if recovered {
+		s.setState(helper.ConnectionWatching, nil)
+	}
+}
+
+// Get returns the object with the provided namespace and name
+// from the informer cache. For cluster scoped resources the
+// namespace should be empty.
+func (s *sharedInformer) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	key := name
+	if namespace != "" {
+		key = fmt.Sprintf("%s/%s", namespace, name)
+	}
+	obj, exists, err := s.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%q not found", key)
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
+
+// informerCache keeps track of all the informers that have
+// been created so that panels watching the same data share
+// a single watch and cache instead of each creating their own
+type informerCache struct {
+	dynamicClient  dynamic.Interface
+	metadataClient metadata.Interface
+	mutex          *sync.Mutex
+	informers      map[informerKey]*sharedInformer
+}
+
+func newInformerCache(dynamicClient dynamic.Interface, metadataClient metadata.Interface) *informerCache {
+	return &informerCache{
+		dynamicClient:  dynamicClient,
+		metadataClient: metadataClient,
+		mutex:          &sync.Mutex{},
+		informers:      map[informerKey]*sharedInformer{},
+	}
+}
+
+// informerFor registers the handler on an informer that covers the request.
+// If an existing informer covers the request, the handler is registered with
+// a filter so that it only receives events for the objects in the request.
+// Otherwise, a new informer scoped to exactly the request is created.
+func (c *informerCache) informerFor(req informerRequest, handler cache.ResourceEventHandler) (*sharedInformer, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := req.key()
+	inf, ok := c.informers[key]
+	if !ok {
+		inf = c.narrowestCovering(key)
+	}
+
+	if inf == nil {
+		informer := c.newInformer(req)
+		err := informer.SetTransform(pruneTransform(req.prune))
+		if err != nil {
+			return nil, fmt.Errorf("setting informer transform: %w", err)
+		}
+		inf, err = newSharedInformer(informer)
+		if err != nil {
+			return nil, err
+		}
+		c.informers[key] = inf
+	}
+
+	_, err := inf.informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			u, ok := unstructuredFrom(obj)
+			if !ok {
+				return false
+			}
+			return req.matches(u)
+		},
+		Handler: handler,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding event handler to informer: %w", err)
+	}
+
+	return inf, nil
+}
+
+// narrowestCovering returns the informer that covers the key while
+// watching the fewest other objects, or nil if none covers it. Ties
+// are broken by the keys so that the same informer is always chosen.
+func (c *informerCache) narrowestCovering(key informerKey) *sharedInformer {
+	var best *informerKey
+	for k := range c.informers {
+		if !k.covers(key) {
+			continue
+		}
+		if best == nil || k.narrowerThan(*best) {
+			candidate := k
+			best = &candidate
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return c.informers[*best]
+}
+
+func (c *informerCache) newInformer(req informerRequest) cache.SharedIndexInformer {
+	indexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+	tweak := func(lo *v1.ListOptions) {
+		if req.labelSelector != nil {
+			lo.LabelSelector = req.labelSelector.String()
+		}
+		selectors := []fields.Selector{}
+		if req.fieldSelector != nil && !req.fieldSelector.Empty() {
+			selectors = append(selectors, req.fieldSelector)
+		}
+		if req.name != "" {
+			selectors = append(selectors, fields.OneTermEqualSelector("metadata.name", req.name))
+		}
+		if len(selectors) > 0 {
+			lo.FieldSelector = fields.AndSelectors(selectors...).String()
+		}
+	}
+
+	if req.metadataOnly {
+		return metadatainformer.NewFilteredMetadataInformer(
+			c.metadataClient,
+			req.gvr,
+			req.namespace,
+			defaultResyncPeriod,
+			indexers,
+			tweak,
+		).Informer()
+	}
+
+	return dynamicinformer.NewFilteredDynamicInformer(
+		c.dynamicClient,
+		req.gvr,
+		req.namespace,
+		defaultResyncPeriod,
+		indexers,
+		tweak,
+	).Informer()
+}
+
+// pruneTransform returns a cache.TransformFunc that reduces the memory
+// footprint of cached objects. Objects from metadata informers are converted
+// to *unstructured.Unstructured so that handlers can treat all objects the same.
+// The managedFields and any fields at the provided dot notation paths are removed.
+func pruneTransform(prune []string) cache.TransformFunc {
+	return func(obj interface{}) (interface{}, error) {
+		var u *unstructured.Unstructured
+		switch o := obj.(type) {
+		case *unstructured.Unstructured:
+			u = o
+		case *v1.PartialObjectMetadata:
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+			if err != nil {
+				return nil, fmt.Errorf("converting object metadata to unstructured: %w", err)
+			}
+			u = &unstructured.Unstructured{Object: content}
+		default:
+			// tombstones contain objects that have already been transformed
+			return obj, nil
+		}
+
+		unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+		for _, path := range prune {
+			unstructured.RemoveNestedField(u.Object, strings.Split(path, ".")...)
+		}
+		return u, nil
+	}
+}
+
+// isMetadataPath returns whether or not the dot notation
+// path refers to a field within the object metadata
+func isMetadataPath(path string) bool {
+	return path == "metadata" || strings.HasPrefix(path, "metadata.")
+}
+
+// unstructuredFrom returns the *unstructured.Unstructured for
+// an informer event object, unwrapping any tombstones
+func unstructuredFrom(obj interface{}) (*unstructured.Unstructured, bool) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	return u, ok
+}
+
+// resynced returns whether or not an update was only sent because
+// the informer resynced, in which case the object still has the
+// resource version it had before
+func resynced(oldObj, newObj interface{}) bool {
+	oldU, ok := unstructuredFrom(oldObj)
+	if !ok {
+		return false
+	}
+	newU, ok := unstructuredFrom(newObj)
+	if !ok {
+		return false
+	}
+	return oldU.GetResourceVersion() != "" && oldU.GetResourceVersion() == newU.GetResourceVersion()
+}
@@ -0,0 +1,230 @@
+package datastream
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+var podsGVR = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func TestInformerKeyCovers(t *testing.T) {
+	all := informerKey{gvr: podsGVR}
+	ns := informerKey{gvr: podsGVR, namespace: "default"}
+	selected := informerKey{gvr: podsGVR, namespace: "default", labelSelector: "app=foo"}
+	named := informerKey{gvr: podsGVR, namespace: "default", name: "foo"}
+	fielded := informerKey{gvr: podsGVR, namespace: "default", fieldSelector: "status.phase!=Running"}
+	fieldedNamed := informerKey{gvr: podsGVR, namespace: "default", fieldSelector: "status.phase!=Running", name: "foo"}
+
+	assert.True(t, all.covers(ns))
+	assert.True(t, all.covers(named))
+	assert.True(t, ns.covers(selected))
+	assert.True(t, ns.covers(named))
+	assert.False(t, ns.covers(all))
+	assert.False(t, selected.covers(ns))
+	assert.False(t, named.covers(ns))
+	assert.False(t, all.covers(fielded))
+	assert.False(t, fielded.covers(ns))
+	assert.True(t, fielded.covers(fieldedNamed))
+	assert.False(t, all.covers(informerKey{gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}}))
+}
+
+func TestInformerRequestMatches(t *testing.T) {
+	u := &unstructured.Unstructured{}
+	u.SetNamespace("default")
+	u.SetName("foo")
+	u.SetLabels(map[string]string{"app": "foo"})
+
+	assert.True(t, informerRequest{gvr: podsGVR}.matches(u))
+	assert.True(t, informerRequest{gvr: podsGVR, namespace: "default", name: "foo"}.matches(u))
+	assert.True(t, informerRequest{gvr: podsGVR, labelSelector: labels.SelectorFromSet(labels.Set{"app": "foo"})}.matches(u))
+	assert.False(t, informerRequest{gvr: podsGVR, namespace: "other"}.matches(u))
+	assert.False(t, informerRequest{gvr: podsGVR, name: "bar"}.matches(u))
+	assert.False(t, informerRequest{gvr: podsGVR, labelSelector: labels.SelectorFromSet(labels.Set{"app": "bar"})}.matches(u))
+}
+
+func TestInformerCacheSharesInformers(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList",
+	})
+	informers := newInformerCache(client, nil)
+
+	t.Log("namespace wide informer is created")
+	nsInf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "default"}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+
+	t.Log("item in the same namespace reuses the namespace wide informer")
+	itemInf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "default", name: "foo"}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+	assert.Same(t, nsInf, itemInf)
+
+	t.Log("item in a different namespace gets a new informer")
+	otherInf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "other", name: "foo"}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+	assert.NotSame(t, nsInf, otherInf)
+	assert.Len(t, informers.informers, 2)
+}
+
+func TestInformerCachePrefersNarrowestInformer(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList",
+	})
+	informers := newInformerCache(client, nil)
+	nsInf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "default"}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+	allInf, err := informers.informerFor(informerRequest{gvr: podsGVR}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+	assert.NotSame(t, nsInf, allInf)
+
+	t.Log("the namespace informer is always chosen over the cluster wide informer")
+	for i := 0; i < 20; i++ {
+		itemInf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "default", name: "foo"}, cache.ResourceEventHandlerFuncs{})
+		assert.NoError(t, err)
+		assert.Same(t, nsInf, itemInf)
+	}
+
+	t.Log("informers that are as narrow are ordered by their keys")
+	a := informerKey{gvr: podsGVR, namespace: "a"}
+	b := informerKey{gvr: podsGVR, namespace: "b"}
+	assert.True(t, a.narrowerThan(b))
+	assert.False(t, b.narrowerThan(a))
+	assert.True(t, informerKey{gvr: podsGVR, namespace: "a", prune: "status"}.narrowerThan(a))
+}
+
+func TestInformerKeyCoversMetadataAndPrune(t *testing.T) {
+	full := informerKey{gvr: podsGVR}
+	meta := informerKey{gvr: podsGVR, metadataOnly: true}
+	prunedStatus := informerKey{gvr: podsGVR, prune: "status"}
+	prunedLabels := informerKey{gvr: podsGVR, prune: "metadata.labels"}
+
+	assert.True(t, full.covers(meta))
+	assert.False(t, meta.covers(full))
+	assert.True(t, full.covers(prunedStatus))
+	assert.False(t, prunedStatus.covers(full))
+	assert.True(t, prunedStatus.covers(meta))
+	assert.False(t, prunedLabels.covers(meta))
+}
+
+func TestPruneTransform(t *testing.T) {
+	transform := pruneTransform([]string{"status.conditions"})
+
+	t.Log("unstructured objects have managedFields and pruned paths removed")
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":          "foo",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"status": map[string]interface{}{
+			"phase":      "Running",
+			"conditions": []interface{}{},
+		},
+	}}
+	obj, err := transform(u)
+	assert.NoError(t, err)
+	transformed := obj.(*unstructured.Unstructured)
+	assert.Equal(t, "foo", transformed.GetName())
+	assert.Nil(t, transformed.GetManagedFields())
+	_, found, _ := unstructured.NestedFieldNoCopy(transformed.Object, "status", "conditions")
+	assert.False(t, found)
+	phase, _, _ := unstructured.NestedString(transformed.Object, "status", "phase")
+	assert.Equal(t, "Running", phase)
+
+	t.Log("object metadata is converted to unstructured")
+	pom := &v1.PartialObjectMetadata{ObjectMeta: v1.ObjectMeta{
+		Name:          "bar",
+		Namespace:     "default",
+		ManagedFields: []v1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}}
+	obj, err = transform(pom)
+	assert.NoError(t, err)
+	transformed = obj.(*unstructured.Unstructured)
+	assert.Equal(t, "bar", transformed.GetName())
+	assert.Equal(t, "default", transformed.GetNamespace())
+	assert.Nil(t, transformed.GetManagedFields())
+
+	t.Log("tombstones are passed through")
+	tombstone := cache.DeletedFinalStateUnknown{Key: "default/bar", Obj: transformed}
+	obj, err = transform(tombstone)
+	assert.NoError(t, err)
+	assert.Equal(t, tombstone, obj)
+}
+
+func TestConnectionMerger(t *testing.T) {
+	recorder := &stateRecorder{}
+	merger := newConnectionMerger(recorder, 2)
+	first, second := merger.listener(0), merger.listener(1)
+
+	t.Log("the panel is syncing until every informer is watching")
+	first.SetConnectionState(helper.ConnectionWatching, nil)
+	second.SetConnectionState(helper.ConnectionSyncing, nil)
+	assert.Equal(t, helper.ConnectionSyncing, recorder.state)
+	second.SetConnectionState(helper.ConnectionWatching, nil)
+	assert.Equal(t, helper.ConnectionWatching, recorder.state)
+
+	t.Log("the worst state and its error are reported")
+	forbidden := errors.New("no access")
+	first.SetConnectionState(helper.ConnectionForbidden, forbidden)
+	second.SetConnectionState(helper.ConnectionReconnecting, errors.New("connection refused"))
+	assert.Equal(t, helper.ConnectionForbidden, recorder.state)
+	assert.Equal(t, forbidden, recorder.err)
+
+	t.Log("recovering informers are no longer reported")
+	first.SetConnectionState(helper.ConnectionWatching, nil)
+	assert.Equal(t, helper.ConnectionReconnecting, recorder.state)
+}
+
+type stateRecorder struct {
+	state helper.ConnectionState
+	err   error
+}
+
+func (s *stateRecorder) SetConnectionState(state helper.ConnectionState, err error) {
+	s.state = state
+	s.err = err
+}
+
+func TestSharedInformerConnectionState(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList",
+	})
+	informers := newInformerCache(client, nil)
+	inf, err := informers.informerFor(informerRequest{gvr: podsGVR, namespace: "default"}, cache.ResourceEventHandlerFuncs{})
+	assert.NoError(t, err)
+
+	t.Log("listeners start with the current state")
+	inf.setState(helper.ConnectionSyncing, nil)
+	first := &stateRecorder{}
+	inf.addListener(first)
+	assert.Equal(t, helper.ConnectionSyncing, first.state)
+
+	t.Log("every listener is notified of changes")
+	second := &stateRecorder{}
+	inf.addListener(second)
+	inf.recover()
+	assert.Equal(t, helper.ConnectionWatching, first.state)
+	assert.Equal(t, helper.ConnectionWatching, second.state)
+
+	t.Log("forbidden watch errors are reported as forbidden")
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", errors.New("no access"))
+	inf.watchErrorHandler(nil, forbidden)
+	assert.Equal(t, helper.ConnectionForbidden, first.state)
+	assert.Equal(t, forbidden, first.err)
+
+	t.Log("other watch errors are reported as reconnecting")
+	inf.watchErrorHandler(nil, errors.New("connection refused"))
+	assert.Equal(t, helper.ConnectionReconnecting, second.state)
+
+	t.Log("the informer does not recover without a successful list or watch")
+	inf.recover()
+	assert.Equal(t, helper.ConnectionReconnecting, second.state)
+}
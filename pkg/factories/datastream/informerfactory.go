@@ -8,6 +8,7 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 )
@@ -60,6 +61,11 @@ func NewDatastreamFactory(cfg *rest.Config) (DatastreamFactory, error) {
 		return nil, fmt.Errorf("error creating dynamic client: %w", err)
 	}
 
+	mClient, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating metadata client: %w", err)
+	}
+
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("creating kubernetes.Clientset: %w", err)
@@ -75,11 +81,12 @@ func NewDatastreamFactory(cfg *rest.Config) (DatastreamFactory, error) {
 		return nil, fmt.Errorf("error getting API group resources: %w", err)
 	}
 	rm := restmapper.NewDiscoveryRESTMapper(gr)
+	informers := newInformerCache(dClient, mClient)
 
 	return &datastreamFactory{
 		datastreamFactoryFuncs: []DatastreamFactoryFunc{
-			ItemDatastreamFunc(dClient, rm),
-			TableDatastreamFunc(dClient, rm),
+			ItemDatastreamFunc(informers, rm),
+			TableDatastreamFunc(informers, rm),
 			LogsDatastreamFunc(kubeClient, dClient, rm),
 		},
 	}, nil
@@ -0,0 +1,36 @@
+package datastream
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/everettraven/buoy/pkg/charm/models/panels/item"
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestItemHandlerSkipsResyncs(t *testing.T) {
+	panel := item.New(buoytypes.Item{}, viewport.New(50, 50), item.Styles{})
+	handler := itemHandler(panel)
+	pod := func(resourceVersion string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetName("foo")
+		u.SetResourceVersion(resourceVersion)
+		return u
+	}
+
+	handler.OnAdd(pod("1"), false)
+	count, _ := panel.Activity()
+	assert.Equal(t, 1, count)
+
+	t.Log("resyncs with the same resource version aren't activity")
+	handler.OnUpdate(pod("1"), pod("1"))
+	count, _ = panel.Activity()
+	assert.Equal(t, 1, count)
+
+	t.Log("updates with a new resource version are")
+	handler.OnUpdate(pod("1"), pod("2"))
+	count, _ = panel.Activity()
+	assert.Equal(t, 2, count)
+}
@@ -4,10 +4,17 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -18,21 +25,151 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// logReconnectDelay is how long to wait
+// before reopening a log stream that ended
+const logReconnectDelay = 5 * time.Second
+
 var _ Datastream = &logDatastream{}
+var _ Pauser = &logDatastream{}
+
+// Pauser is implemented by datastreams that
+// can be temporarily stopped and resumed
+type Pauser interface {
+	Pause()
+	Resume()
+}
 
 type logDatastream struct {
-	logReadCloser io.ReadCloser
-	contentAdder  ContentAdder
+	typedClient *kubernetes.Clientset
+	pod         *v1.Pod
+	log         Log
+	mutex       *sync.Mutex
+	cancel      context.CancelFunc
+	paused      bool
+	// lastLine is the timestamp of the last line added and
+	// lastLineCount the number of lines added with it
+	lastLine      time.Time
+	lastLineCount int
 }
 
 func (l *logDatastream) Run(stopCh <-chan struct{}) {
-	go streamLogs(l.logReadCloser, l.contentAdder)
+	l.stream(nil)
+}
+
+// Pause stops streaming logs until Resume is called
+func (l *logDatastream) Pause() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.cancel == nil {
+		return
+	}
+	l.cancel()
+	l.cancel = nil
+	l.paused = true
+	l.log.SetConnectionState(helper.ConnectionPaused, nil)
+}
+
+// Resume starts streaming logs again from
+// the last line added before the stream was paused
+func (l *logDatastream) Resume() {
+	l.mutex.Lock()
+	paused := l.paused
+	l.paused = false
+	l.mutex.Unlock()
+	if !paused {
+		return
+	}
+	l.stream(l.since())
+}
+
+// since returns the time of the last line added, if any
+func (l *logDatastream) since() *metav1.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.lastLine.IsZero() {
+		return nil
+	}
+	since := metav1.NewTime(l.lastLine)
+	return &since
+}
+
+// stream starts streaming logs in the background. If sinceTime
+// is provided only logs newer than sinceTime are streamed. When
+// the stream ends or fails it is reopened from the last line added,
+// unless access to the logs is forbidden or the container terminated.
+func (l *logDatastream) stream(sinceTime *metav1.Time) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.mutex.Lock()
+	l.cancel = cancel
+	l.mutex.Unlock()
+
+	go func() {
+		for {
+			l.log.SetConnectionState(helper.ConnectionSyncing, nil)
+			rc, err := logsForPod(ctx, l.typedClient, l.pod, l.log.Container(), sinceTime)
+			if err == nil {
+				l.log.SetConnectionState(helper.ConnectionWatching, nil)
+				err = l.addLines(rc)
+				sinceTime = l.since()
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if apierrors.IsForbidden(err) {
+				l.log.SetConnectionState(helper.ConnectionForbidden, err)
+				l.log.SetError(fmt.Errorf("error getting logs for pod: %w", err))
+				return
+			}
+			if errors.Is(err, io.EOF) && l.terminated(ctx) {
+				l.log.SetConnectionState(helper.ConnectionEnded, nil)
+				return
+			}
+			l.log.SetConnectionState(helper.ConnectionReconnecting, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(logReconnectDelay):
+			}
+		}
+	}()
+}
+
+// terminated returns whether the container the logs are streamed
+// from has terminated and won't be restarted, or was deleted
+func (l *logDatastream) terminated(ctx context.Context) bool {
+	pod, err := l.typedClient.CoreV1().Pods(l.pod.Namespace).Get(ctx, l.pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return apierrors.IsNotFound(err)
+	}
+	return containerTerminated(pod, l.log.Container())
+}
+
+// containerTerminated returns whether the container of the pod, or its
+// first container if none is provided, won't write any more logs
+func containerTerminated(pod *v1.Pod, container string) bool {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return true
+	}
+	if pod.Spec.RestartPolicy != v1.RestartPolicyNever {
+		return false
+	}
+	if container == "" && len(pod.Spec.Containers) > 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			return status.State.Terminated != nil
+		}
+	}
+	return false
 }
 
 type Log interface {
 	Key() types.NamespacedName
 	GVK() schema.GroupVersionKind
 	Container() string
+	SetError(error)
+	SetConnectionState(helper.ConnectionState, error)
 	ContentAdder
 }
 
@@ -48,13 +185,11 @@ func LogsDatastreamFunc(typedClient *kubernetes.Clientset, dynamicClient *dynami
 			if err != nil {
 				return nil, fmt.Errorf("error getting pod: %w", err)
 			}
-			rc, err := logsForPod(typedClient, pod, log.Container())
-			if err != nil {
-				return nil, fmt.Errorf("error getting logs for pod: %w", err)
-			}
 			return &logDatastream{
-				logReadCloser: rc,
-				contentAdder:  log,
+				typedClient: typedClient,
+				pod:         pod,
+				log:         log,
+				mutex:       &sync.Mutex{},
 			}, nil
 		}
 
@@ -78,14 +213,11 @@ func LogsDatastreamFunc(typedClient *kubernetes.Clientset, dynamicClient *dynami
 		if len(pods.Items) == 0 {
 			return nil, fmt.Errorf("no pods found for object")
 		}
-		pod := &pods.Items[0]
-		rc, err := logsForPod(typedClient, pod, log.Container())
-		if err != nil {
-			return nil, fmt.Errorf("error getting logs for pod: %w", err)
-		}
 		return &logDatastream{
-			logReadCloser: rc,
-			contentAdder:  log,
+			typedClient: typedClient,
+			pod:         &pods.Items[0],
+			log:         log,
+			mutex:       &sync.Mutex{},
 		}, nil
 	}
 }
@@ -114,20 +246,66 @@ type ContentAdder interface {
 	AddContent(string)
 }
 
-func streamLogs(rc io.ReadCloser, ca ContentAdder) {
+// addLines adds every line read from rc until it is closed,
+// returning io.EOF if the stream ended cleanly. Lines start with
+// their timestamp, which is removed. Logs can only be requested
+// from the start of a second, so when a stream is resumed the
+// lines up to the last line added are dropped instead of being
+// added again. Every line after those is added, even if it has
+// the same timestamp as the line before it.
+func (l *logDatastream) addLines(rc io.ReadCloser) error {
+	defer rc.Close()
+	l.mutex.Lock()
+	resumeAt, resumeCount := l.lastLine, l.lastLineCount
+	l.mutex.Unlock()
+	resuming := !resumeAt.IsZero()
+	seen := 0
+
 	scanner := bufio.NewScanner(rc)
 	for scanner.Scan() {
-		ca.AddContent(scanner.Text())
+		timestamp, line, _ := strings.Cut(scanner.Text(), " ")
+		t, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			l.log.AddContent(scanner.Text())
+			continue
+		}
+		if resuming && !t.After(resumeAt) {
+			if t.Before(resumeAt) {
+				continue
+			}
+			// lines sharing the timestamp of the last line
+			// added were added up to the number counted
+			if seen++; seen <= resumeCount {
+				continue
+			}
+		}
+		resuming = false
+
+		l.mutex.Lock()
+		if t.Equal(l.lastLine) {
+			l.lastLineCount++
+		} else {
+			l.lastLine = t
+			l.lastLineCount = 1
+		}
+		l.mutex.Unlock()
+		l.log.AddContent(line)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
 	}
+	return io.EOF
 }
 
-func logsForPod(kc *kubernetes.Clientset, pod *v1.Pod, container string) (io.ReadCloser, error) {
+func logsForPod(ctx context.Context, kc *kubernetes.Clientset, pod *v1.Pod, container string, sinceTime *metav1.Time) (io.ReadCloser, error) {
 	req := kc.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &v1.PodLogOptions{
-		Container: container,
-		Follow:    true,
+		Container:  container,
+		Follow:     true,
+		SinceTime:  sinceTime,
+		Timestamps: true,
 	})
 
-	rc, err := req.Stream(context.Background())
+	rc, err := req.Stream(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("fetching logs for %s/%s: %w", pod.Namespace, pod.Name, err)
 	}
@@ -0,0 +1,102 @@
+package datastream
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type logRecorder struct {
+	lines []string
+}
+
+func (l *logRecorder) Key() types.NamespacedName                        { return types.NamespacedName{} }
+func (l *logRecorder) GVK() schema.GroupVersionKind                     { return schema.GroupVersionKind{} }
+func (l *logRecorder) Container() string                                { return "" }
+func (l *logRecorder) SetError(error)                                   {}
+func (l *logRecorder) SetConnectionState(helper.ConnectionState, error) {}
+func (l *logRecorder) AddContent(line string)                           { l.lines = append(l.lines, line) }
+
+func TestLogDatastreamAddLines(t *testing.T) {
+	log := &logRecorder{}
+	ds := &logDatastream{log: log, mutex: &sync.Mutex{}}
+
+	t.Log("timestamps are removed from the lines")
+	err := ds.addLines(io.NopCloser(strings.NewReader(
+		"2024-01-01T10:00:00.100000000Z first\n" +
+			"2024-01-01T10:00:00.200000000Z second\n")))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"first", "second"}, log.lines)
+	assert.Equal(t, "2024-01-01T10:00:00Z", ds.since().UTC().Format("2006-01-02T15:04:05Z"))
+
+	t.Log("lines from the same second that were already added are dropped when resuming")
+	err = ds.addLines(io.NopCloser(strings.NewReader(
+		"2024-01-01T10:00:00.100000000Z first\n" +
+			"2024-01-01T10:00:00.200000000Z second\n" +
+			"2024-01-01T10:00:00.300000000Z third\n")))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"first", "second", "third"}, log.lines)
+
+	t.Log("lines with the same timestamp are all added")
+	log = &logRecorder{}
+	ds = &logDatastream{log: log, mutex: &sync.Mutex{}}
+	err = ds.addLines(io.NopCloser(strings.NewReader(
+		"2024-01-01T10:00:01.000000000Z first\n" +
+			"2024-01-01T10:00:01.000000000Z second\n")))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"first", "second"}, log.lines)
+
+	t.Log("only the lines with that timestamp that were already added are dropped when resuming")
+	err = ds.addLines(io.NopCloser(strings.NewReader(
+		"2024-01-01T10:00:01.000000000Z first\n" +
+			"2024-01-01T10:00:01.000000000Z second\n" +
+			"2024-01-01T10:00:01.000000000Z third\n" +
+			"2024-01-01T10:00:02.000000000Z fourth\n")))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, log.lines)
+
+	t.Log("lines without a timestamp are added as they are")
+	err = ds.addLines(io.NopCloser(strings.NewReader("no timestamp\n")))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "no timestamp", log.lines[len(log.lines)-1])
+}
+
+func TestContainerTerminated(t *testing.T) {
+	pod := func(phase v1.PodPhase, policy v1.RestartPolicy, terminated ...string) *v1.Pod {
+		p := &v1.Pod{
+			Spec: v1.PodSpec{
+				RestartPolicy: policy,
+				Containers:    []v1.Container{{Name: "app"}, {Name: "sidecar"}},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+		for _, c := range p.Spec.Containers {
+			status := v1.ContainerStatus{Name: c.Name, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}}
+			if slices.Contains(terminated, c.Name) {
+				status.State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{}}
+			}
+			p.Status.ContainerStatuses = append(p.Status.ContainerStatuses, status)
+		}
+		return p
+	}
+
+	t.Log("completed pods have terminated")
+	assert.True(t, containerTerminated(pod(v1.PodSucceeded, v1.RestartPolicyAlways), "app"))
+	assert.True(t, containerTerminated(pod(v1.PodFailed, v1.RestartPolicyOnFailure), ""))
+
+	t.Log("containers of running pods are restarted unless the restart policy is never")
+	assert.False(t, containerTerminated(pod(v1.PodRunning, v1.RestartPolicyAlways, "app"), "app"))
+	assert.True(t, containerTerminated(pod(v1.PodRunning, v1.RestartPolicyNever, "app"), "app"))
+
+	t.Log("the first container is checked when none is provided")
+	assert.True(t, containerTerminated(pod(v1.PodRunning, v1.RestartPolicyNever, "app"), ""))
+	assert.False(t, containerTerminated(pod(v1.PodRunning, v1.RestartPolicyNever, "sidecar"), ""))
+}
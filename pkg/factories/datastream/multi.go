@@ -0,0 +1,78 @@
+package datastream
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/everettraven/buoy/pkg/charm/models/helper"
+)
+
+var _ Datastream = datastreams{}
+
+// datastreams runs several datastreams as one,
+// i.e the informers of each namespace of a table
+type datastreams []Datastream
+
+func (d datastreams) Run(stopCh <-chan struct{}) {
+	wg := &sync.WaitGroup{}
+	for _, ds := range d {
+		wg.Add(1)
+		go func(ds Datastream) {
+			defer wg.Done()
+			ds.Run(stopCh)
+		}(ds)
+	}
+	wg.Wait()
+}
+
+// connectionSeverity orders the connection states from best to worst
+var connectionSeverity = []helper.ConnectionState{
+	helper.ConnectionIdle,
+	helper.ConnectionWatching,
+	helper.ConnectionSyncing,
+	helper.ConnectionReconnecting,
+	helper.ConnectionForbidden,
+}
+
+// connectionMerger combines the connection states of several
+// informers into the connection state of a single panel. The
+// panel is in the worst state of any of the informers.
+type connectionMerger struct {
+	setter ConnectionStateSetter
+	mutex  *sync.Mutex
+	states []helper.ConnectionState
+	errs   []error
+}
+
+func newConnectionMerger(setter ConnectionStateSetter, n int) *connectionMerger {
+	return &connectionMerger{
+		setter: setter,
+		mutex:  &sync.Mutex{},
+		states: make([]helper.ConnectionState, n),
+		errs:   make([]error, n),
+	}
+}
+
+// listener returns the ConnectionStateSetter of the i-th informer
+func (c *connectionMerger) listener(i int) ConnectionStateSetter {
+	return connectionStateFunc(func(state helper.ConnectionState, err error) {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		c.states[i] = state
+		c.errs[i] = err
+		worst := 0
+		for j, s := range c.states {
+			if slices.Index(connectionSeverity, s) > slices.Index(connectionSeverity, c.states[worst]) {
+				worst = j
+			}
+		}
+		c.setter.SetConnectionState(c.states[worst], c.errs[worst])
+	})
+}
+
+// connectionStateFunc is a function that implements ConnectionStateSetter
+type connectionStateFunc func(helper.ConnectionState, error)
+
+func (f connectionStateFunc) SetConnectionState(state helper.ConnectionState, err error) {
+	f(state, err)
+}
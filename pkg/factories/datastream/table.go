@@ -1,18 +1,17 @@
 package datastream
 
 import (
+	"context"
 	"fmt"
-	"time"
 
 	"github.com/everettraven/buoy/pkg/charm/models/panels/table"
 	"k8s.io/apimachinery/pkg/api/meta"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/yaml"
 )
@@ -21,12 +20,41 @@ type Table interface {
 	GVK() schema.GroupVersionKind
 	AddOrUpdate(*unstructured.Unstructured)
 	DeleteRow(types.UID)
-	Namespace() string
-	LabelSelector() labels.Set
+	Namespaces() []string
+	LabelSelector() labels.Selector
+	FieldSelector() fields.Selector
+	MetadataOnly() bool
+	PrunePaths() []string
 	SetViewActionFunc(table.ViewActionFunc)
 }
 
-func TableDatastreamFunc(dynamicClient *dynamic.DynamicClient, restMapper meta.RESTMapper) DatastreamFactoryFunc {
+// tableHandler returns the handler that adds, updates and deletes
+// the rows of the table. Updates sent when the informer resyncs
+// are skipped since the objects haven't changed.
+func tableHandler(tbl Table) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			u := obj.(*unstructured.Unstructured)
+			tbl.AddOrUpdate(u)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if resynced(oldObj, newObj) {
+				return
+			}
+			u := newObj.(*unstructured.Unstructured)
+			tbl.AddOrUpdate(u)
+		},
+		DeleteFunc: func(obj interface{}) {
+			u, ok := unstructuredFrom(obj)
+			if !ok {
+				return
+			}
+			tbl.DeleteRow(u.GetUID())
+		},
+	}
+}
+
+func TableDatastreamFunc(informers *informerCache, restMapper meta.RESTMapper) DatastreamFactoryFunc {
 	return func(obj interface{}) (Datastream, error) {
 		tbl, ok := obj.(Table)
 		if !ok {
@@ -38,50 +66,74 @@ func TableDatastreamFunc(dynamicClient *dynamic.DynamicClient, restMapper meta.R
 			return nil, fmt.Errorf("error creating resource mapping: %w", err)
 		}
 
-		ns := tbl.Namespace()
+		namespaces := tbl.Namespaces()
 		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
-			ns = ""
+			namespaces = []string{""}
 		}
-		infFact := dynamicinformer.NewFilteredDynamicSharedInformerFactory(
-			dynamicClient,
-			1*time.Minute,
-			ns,
-			dynamicinformer.TweakListOptionsFunc(func(options *v1.ListOptions) {
-				ls := labels.SelectorFromSet(tbl.LabelSelector())
-				options.LabelSelector = ls.String()
-			}),
-		)
+		handler := tableHandler(tbl)
 
-		inf := infFact.ForResource(mapping.Resource)
-		_, err = inf.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-				u := obj.(*unstructured.Unstructured)
-				tbl.AddOrUpdate(u)
-			},
-			UpdateFunc: func(oldObj, newObj interface{}) {
-				u := newObj.(*unstructured.Unstructured)
-				tbl.AddOrUpdate(u)
-			},
-			DeleteFunc: func(obj interface{}) {
-				u := obj.(*unstructured.Unstructured)
-				tbl.DeleteRow(u.GetUID())
-			},
-		})
-		if err != nil {
-			return nil, err
+		metadataOnly := tbl.MetadataOnly()
+		// each namespace has its own informer, merged into the same table
+		streams := datastreams{}
+		byNamespace := map[string]*sharedInformer{}
+		var merger *connectionMerger
+		if setter, ok := obj.(ConnectionStateSetter); ok {
+			merger = newConnectionMerger(setter, len(namespaces))
 		}
+		for i, ns := range namespaces {
+			inf, err := informers.informerFor(informerRequest{
+				gvr:           mapping.Resource,
+				namespace:     ns,
+				labelSelector: tbl.LabelSelector(),
+				fieldSelector: tbl.FieldSelector(),
+				metadataOnly:  metadataOnly,
+				prune:         tbl.PrunePaths(),
+			}, handler)
+			if err != nil {
+				return nil, err
+			}
+			if merger != nil {
+				inf.addListener(merger.listener(i))
+			}
+			streams = append(streams, inf)
+			byNamespace[ns] = inf
+		}
+
 		tbl.SetViewActionFunc(func(row *table.RowInfo) (string, error) {
 			name := row.Identifier.String()
+			namespace := row.Identifier.Namespace
 			if mapping.Scope.Name() == meta.RESTScopeNameRoot {
 				name = row.Identifier.Name
+				namespace = ""
 			}
 
-			obj, err := inf.Lister().Get(name)
+			inf, ok := byNamespace[namespace]
+			if !ok {
+				// the informer of every namespace
+				inf, ok = byNamespace[""]
+			}
+			if !ok {
+				return "", fmt.Errorf("fetching definition for %q: namespace %q is not watched", name, namespace)
+			}
+			obj, err := inf.Get(namespace, row.Identifier.Name)
 			if err != nil {
 				return "", fmt.Errorf("fetching definition for %q: %w", name, err)
 			}
+			// the resource may have been deleted and
+			// recreated with the same name since
+			if obj.GetUID() != row.UID {
+				return "", fmt.Errorf("fetching definition for %q: resource no longer exists", name)
+			}
+			// the cache of metadata informers only has the
+			// metadata, so the full resource is fetched instead
+			if metadataOnly {
+				obj, err = informers.dynamicClient.Resource(mapping.Resource).Namespace(namespace).Get(context.Background(), row.Identifier.Name, metav1.GetOptions{})
+				if err != nil {
+					return "", fmt.Errorf("fetching definition for %q: %w", name, err)
+				}
+			}
 
-			itemJSON, err := obj.(*unstructured.Unstructured).MarshalJSON()
+			itemJSON, err := obj.MarshalJSON()
 			if err != nil {
 				return "", fmt.Errorf("error marshalling item %q: %w", name, err)
 			}
@@ -93,6 +145,9 @@ func TableDatastreamFunc(dynamicClient *dynamic.DynamicClient, restMapper meta.R
 
 			return string(itemYAML), nil
 		})
-		return inf.Informer(), nil
+		if len(streams) == 1 {
+			return streams[0], nil
+		}
+		return streams, nil
 	}
 }
@@ -0,0 +1,121 @@
+package datastream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/everettraven/buoy/pkg/charm/models/panels/table"
+	buoytypes "github.com/everettraven/buoy/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// viewActionRecorder keeps the view action set on the table
+type viewActionRecorder struct {
+	*table.Model
+	viewAction table.ViewActionFunc
+}
+
+func (v *viewActionRecorder) SetViewActionFunc(vaf table.ViewActionFunc) {
+	v.viewAction = vaf
+}
+
+func TestTableViewActionFetchesFullObject(t *testing.T) {
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"spec":       map[string]interface{}{"nodeName": "node1"},
+	}}
+	pod.SetName("foo")
+	pod.SetNamespace("default")
+	pod.SetUID(types.UID("foo"))
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podsGVR: "PodList",
+	}, pod)
+	informers := newInformerCache(client, nil)
+	podGVK := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(podGVK, meta.RESTScopeNamespace)
+
+	tbl := &viewActionRecorder{Model: table.New(table.DefaultKeys, &buoytypes.Table{
+		PanelBase: buoytypes.PanelBase{Version: "v1", Kind: "Pod"},
+		Columns:   []buoytypes.Column{{Header: "Name", Path: "metadata.name"}},
+	}, table.Styles{})}
+	assert.True(t, tbl.MetadataOnly())
+	ds, err := TableDatastreamFunc(informers, restMapper)(tbl)
+	assert.NoError(t, err)
+
+	t.Log("the metadata informer only caches the metadata")
+	metadata := &unstructured.Unstructured{}
+	metadata.SetName("foo")
+	metadata.SetNamespace("default")
+	metadata.SetUID(types.UID("foo"))
+	assert.NoError(t, ds.(*sharedInformer).informer.GetIndexer().Add(metadata))
+
+	t.Log("viewing the resource shows the full object")
+	content, err := tbl.viewAction(&table.RowInfo{
+		UID:        types.UID("foo"),
+		Identifier: &types.NamespacedName{Namespace: "default", Name: "foo"},
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, content, "nodeName: node1")
+}
+
+func TestTableHandlerSkipsResyncs(t *testing.T) {
+	tbl := table.New(table.DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{{Header: "Name", Path: "metadata.name"}},
+	}, table.Styles{})
+	handler := tableHandler(tbl)
+	pod := func(resourceVersion string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetName("foo")
+		u.SetUID(types.UID("foo"))
+		u.SetResourceVersion(resourceVersion)
+		return u
+	}
+
+	handler.OnAdd(pod("1"), false)
+	count, _ := tbl.Activity()
+	assert.Equal(t, 1, count)
+
+	t.Log("resyncs with the same resource version aren't activity")
+	handler.OnUpdate(pod("1"), pod("1"))
+	count, _ = tbl.Activity()
+	assert.Equal(t, 1, count)
+
+	t.Log("updates with a new resource version are")
+	handler.OnUpdate(pod("1"), pod("2"))
+	count, _ = tbl.Activity()
+	assert.Equal(t, 2, count)
+}
+
+func TestTableHandlerResyncsKeepLastActivity(t *testing.T) {
+	tbl := table.New(table.DefaultKeys, &buoytypes.Table{
+		Columns: []buoytypes.Column{{Header: "Name", Path: "metadata.name"}},
+	}, table.Styles{})
+	handler := tableHandler(tbl)
+	pod := func(resourceVersion string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetName("foo")
+		u.SetUID(types.UID("foo"))
+		u.SetResourceVersion(resourceVersion)
+		return u
+	}
+
+	handler.OnAdd(pod("1"), false)
+	added := tbl.LastActivity()
+	time.Sleep(time.Millisecond)
+
+	t.Log("resyncs don't make the data look more recent than it is")
+	handler.OnUpdate(pod("1"), pod("1"))
+	assert.Equal(t, added, tbl.LastActivity())
+
+	t.Log("updates with a new resource version do")
+	handler.OnUpdate(pod("1"), pod("2"))
+	assert.True(t, tbl.LastActivity().After(added))
+}
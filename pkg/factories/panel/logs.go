@@ -12,6 +12,7 @@ import (
 var _ PanelFactory = &Log{}
 
 type Log struct {
+	keys  logs.KeyMap
 	theme logs.Styles
 }
 
@@ -21,6 +22,9 @@ func (t *Log) ModelForPanel(panel types.Panel) (tea.Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unmarshalling panel to table type: %s", err)
 	}
-	logPanel := logs.New(logs.DefaultKeys, log, t.theme)
+	if err := logs.ValidatePatterns(log); err != nil {
+		return nil, fmt.Errorf("validating log patterns: %w", err)
+	}
+	logPanel := logs.New(t.keys, log, t.theme)
 	return logPanel, nil
 }
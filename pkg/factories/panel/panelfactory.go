@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/buoy/pkg/charm/keymap"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/item"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/logs"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/table"
@@ -28,19 +29,24 @@ func (p *paneler) ModelForPanel(panel types.Panel) (tea.Model, error) {
 	return nil, fmt.Errorf("panel %q has unknown panel type: %q", panel.Name, panel.Type)
 }
 
-func NewPanelFactory(theme styles.Theme) PanelFactory {
+func NewPanelFactory(theme styles.Theme, keys keymap.Keymap) PanelFactory {
 	return &paneler{
 		panelerRegistry: map[string]PanelFactory{
-			types.PanelTypeTable: &Table{theme: table.Styles{
+			types.PanelTypeTable: &Table{keys: keys.Table, theme: table.Styles{
 				SelectedRow:          theme.TableSelectedRowStyle(),
 				SyntaxHighlightDark:  theme.SyntaxHighlightDarkTheme,
 				SyntaxHighlightLight: theme.SyntaxHighlightLightTheme,
+				FilterPrompt:         "/ ",
+				FilterPlaceholder:    "filter, or column:filter",
+				FilterStyle:          theme.TableFilterStyle(),
+				CellErrorStyle:       theme.ErrorStyle(),
+				Palette:              theme.Palette,
 			}},
 			types.PanelTypeItem: &Item{theme: item.Styles{
 				SyntaxHighlightDark:  theme.SyntaxHighlightDarkTheme,
 				SyntaxHighlightLight: theme.SyntaxHighlightLightTheme,
 			}},
-			types.PanelTypeLogs: &Log{theme: logs.Styles{
+			types.PanelTypeLogs: &Log{keys: keys.Logs, theme: logs.Styles{
 				SearchPrompt:              "> ",
 				SearchPlaceholder:         "query",
 				SearchModeStyle:           theme.LogSearchModeStyle(),
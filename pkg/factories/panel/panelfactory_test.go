@@ -3,6 +3,7 @@ package panel
 import (
 	"testing"
 
+	"github.com/everettraven/buoy/pkg/charm/keymap"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/item"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/logs"
 	"github.com/everettraven/buoy/pkg/charm/models/panels/table"
@@ -12,7 +13,7 @@ import (
 )
 
 func TestUnknownPanelType(t *testing.T) {
-	panelFactory := NewPanelFactory(styles.Theme{})
+	panelFactory := NewPanelFactory(styles.Theme{}, keymap.Default())
 	_, err := panelFactory.ModelForPanel(types.Panel{
 		PanelBase: types.PanelBase{
 			Name: "test",
@@ -49,7 +50,7 @@ func TestTablePanel(t *testing.T) {
 	err := panel.UnmarshalJSON([]byte(panelJSON))
 	assert.NoError(t, err)
 
-	panelFactory := NewPanelFactory(styles.Theme{})
+	panelFactory := NewPanelFactory(styles.Theme{}, keymap.Default())
 	tbl, err := panelFactory.ModelForPanel(*panel)
 	assert.NoError(t, err)
 	assert.NotNil(t, tbl)
@@ -73,7 +74,7 @@ func TestItemPanel(t *testing.T) {
 	err := panel.UnmarshalJSON([]byte(panelJSON))
 	assert.NoError(t, err)
 
-	panelFactory := NewPanelFactory(styles.Theme{})
+	panelFactory := NewPanelFactory(styles.Theme{}, keymap.Default())
 	itemModel, err := panelFactory.ModelForPanel(*panel)
 	assert.NoError(t, err)
 	assert.NotNil(t, itemModel)
@@ -97,7 +98,7 @@ func TestLogPanel(t *testing.T) {
 	err := panel.UnmarshalJSON([]byte(panelJSON))
 	assert.NoError(t, err)
 
-	panelFactory := NewPanelFactory(styles.Theme{})
+	panelFactory := NewPanelFactory(styles.Theme{}, keymap.Default())
 	log, err := panelFactory.ModelForPanel(*panel)
 	assert.NoError(t, err)
 	assert.NotNil(t, log)
@@ -12,6 +12,7 @@ import (
 var _ PanelFactory = &Table{}
 
 type Table struct {
+	keys  table.KeyMap
 	theme table.Styles
 }
 
@@ -21,6 +22,24 @@ func (t *Table) ModelForPanel(panel buoytypes.Panel) (tea.Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unmarshalling panel to table type: %s", err)
 	}
-	table := table.New(table.DefaultKeys, tab, t.theme)
+	if err := table.ExpandCustomColumns(tab); err != nil {
+		return nil, fmt.Errorf("expanding custom columns: %w", err)
+	}
+	if err := table.ValidateColumns(tab.Columns); err != nil {
+		return nil, fmt.Errorf("validating table columns: %w", err)
+	}
+	if err := table.ValidateLayout(tab); err != nil {
+		return nil, fmt.Errorf("validating table layout: %w", err)
+	}
+	if err := table.ValidateSort(tab); err != nil {
+		return nil, fmt.Errorf("validating table sort: %w", err)
+	}
+	if err := table.ValidateSelectors(tab); err != nil {
+		return nil, fmt.Errorf("validating table selectors: %w", err)
+	}
+	if err := table.ValidateStyles(tab, t.theme.Palette); err != nil {
+		return nil, fmt.Errorf("validating table styles: %w", err)
+	}
+	table := table.New(t.keys, tab, t.theme)
 	return table, nil
 }
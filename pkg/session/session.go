@@ -0,0 +1,97 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// State is the UI state of a dashboard that
+// is restored the next time it is opened
+type State struct {
+	// Tab is the name of the selected tab
+	Tab string `json:"tab,omitempty"`
+	// Panels is the state of each panel by name
+	Panels map[string]json.RawMessage `json:"panels,omitempty"`
+}
+
+// Store saves the state of dashboards as files in a directory.
+// Each dashboard is keyed by the path or URL it was loaded from.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the directory sessions are stored in,
+// $XDG_STATE_HOME/buoy/sessions or ~/.local/state/buoy/sessions
+func DefaultDir() (string, error) {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("finding home directory: %w", err)
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "buoy", "sessions"), nil
+}
+
+// Key returns the key for a dashboard loaded from path.
+// Local paths are made absolute so that the same dashboard
+// is found regardless of the working directory.
+func Key(path string, remote bool) string {
+	if remote {
+		return path
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// Load returns the saved state of the dashboard with key.
+// An empty state is returned if nothing has been saved.
+func (s *Store) Load(key string) (*State, error) {
+	state := &State{Panels: map[string]json.RawMessage{}}
+	raw, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("reading session: %w", err)
+	}
+	if err := json.Unmarshal(raw, state); err != nil {
+		return state, fmt.Errorf("unmarshalling session: %w", err)
+	}
+	return state, nil
+}
+
+// Save saves the state of the dashboard with key
+func (s *Store) Save(key string, state *State) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshalling session: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating session directory: %w", err)
+	}
+	if err := os.WriteFile(s.path(key), raw, 0o600); err != nil {
+		return fmt.Errorf("writing session: %w", err)
+	}
+	return nil
+}
+
+// path returns the file for key. Keys are hashed
+// since paths and URLs can't be used as file names.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
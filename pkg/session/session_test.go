@@ -0,0 +1,45 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "sessions"))
+
+	t.Log("an empty state is loaded when nothing was saved")
+	state, err := store.Load("/dash.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "", state.Tab)
+	assert.Empty(t, state.Panels)
+
+	t.Log("saved states are loaded by key")
+	err = store.Save("/dash.yaml", &State{Tab: "Pods", Panels: map[string]json.RawMessage{"Pods": json.RawMessage(`{"page":2}`)}})
+	assert.NoError(t, err)
+	state, err = store.Load("/dash.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "Pods", state.Tab)
+	assert.JSONEq(t, `{"page":2}`, string(state.Panels["Pods"]))
+
+	t.Log("other dashboards have their own state")
+	state, err = store.Load("https://example.com/dash.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "", state.Tab)
+
+	t.Log("corrupt states are errors")
+	assert.NoError(t, os.WriteFile(store.path("/dash.yaml"), []byte("{"), 0o600))
+	_, err = store.Load("/dash.yaml")
+	assert.ErrorContains(t, err, "unmarshalling session")
+}
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "https://example.com/dash.yaml", Key("https://example.com/dash.yaml", true))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(wd, "dash.yaml"), Key("dash.yaml", false))
+}
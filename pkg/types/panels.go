@@ -2,7 +2,10 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -12,12 +15,24 @@ const (
 	PanelTypeLogs  = "logs"
 )
 
+const (
+	// StartEager starts fetching data for a panel when the dashboard is loaded
+	StartEager = "eager"
+	// StartLazy starts fetching data for a panel the first time it is shown
+	StartLazy = "lazy"
+)
+
 type PanelBase struct {
 	Name    string `json:"name" yaml:"name"`
 	Group   string `json:"group" yaml:"group"`
 	Version string `json:"version" yaml:"version"`
 	Kind    string `json:"kind" yaml:"kind"`
 	Type    string `json:"type" yaml:"type"`
+	Start   string `json:"start" yaml:"start"`
+	// Section is the name of the section of the dashboard
+	// the panel is shown in. It is named section instead of
+	// group to not be confused with the API group.
+	Section string `json:"section" yaml:"section"`
 }
 
 type Panel struct {
@@ -37,16 +52,142 @@ func (p *Panel) UnmarshalJSON(data []byte) error {
 
 type Table struct {
 	PanelBase
-	Columns       []Column          `json:"columns" yaml:"columns"`
-	Namespace     string            `json:"namespace" yaml:"namespace"`
-	LabelSelector map[string]string `json:"labelSelector" yaml:"labelSelector"`
-	PageSize      int               `json:"pageSize" yaml:"pageSize"`
+	Columns   []Column `json:"columns" yaml:"columns"`
+	Namespace string   `json:"namespace" yaml:"namespace"`
+	// Namespaces shows the resources of several namespaces
+	// in the same table. Used instead of Namespace.
+	Namespaces    []string      `json:"namespaces" yaml:"namespaces"`
+	LabelSelector LabelSelector `json:"labelSelector" yaml:"labelSelector"`
+	// FieldSelector is a field selector in its
+	// string form, i.e status.phase!=Running
+	FieldSelector string   `json:"fieldSelector" yaml:"fieldSelector"`
+	PageSize      int      `json:"pageSize" yaml:"pageSize"`
+	Prune         []string `json:"prune" yaml:"prune"`
+	// SortBy orders the rows by one or more columns. Rows that
+	// are equal for a key are ordered by the keys after it.
+	SortBy []SortKey `json:"sortBy" yaml:"sortBy"`
+	// CustomColumns is a kubectl style custom-columns spec, i.e
+	// NAME:.metadata.name,READY:.status.readyReplicas, that is
+	// added to the columns of the table
+	CustomColumns string `json:"customColumns" yaml:"customColumns"`
+	// RowStyles style whole rows, i.e to show unhealthy
+	// resources in red. The first rule a row matches is applied.
+	RowStyles []RowStyleRule `json:"rowStyles" yaml:"rowStyles"`
+	// FreezeColumns is the number of columns, from the left,
+	// that stay in view while scrolling horizontally. Defaults to 1.
+	FreezeColumns *int `json:"freezeColumns" yaml:"freezeColumns"`
+	// SingleLine shows every row on a single line, cutting long
+	// values short instead of wrapping them over several lines
+	SingleLine bool `json:"singleLine" yaml:"singleLine"`
+}
+
+// LabelSelector selects resources by their labels. It is either a map
+// of labels the resources must have, the string form of a selector,
+// i.e app in (a,b),!legacy, or an object with matchLabels and matchExpressions.
+type LabelSelector struct {
+	// Expression is the string form of the selector
+	Expression string `json:"-" yaml:"-"`
+	metav1.LabelSelector
+}
+
+func (l *LabelSelector) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &l.Expression); err == nil {
+		return nil
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("label selector must be a string, a map of labels or an object with matchLabels and matchExpressions: %w", err)
+	}
+	_, hasLabels := fields["matchLabels"]
+	_, hasExpressions := fields["matchExpressions"]
+	if hasLabels || hasExpressions {
+		return json.Unmarshal(data, &l.LabelSelector)
+	}
+	return json.Unmarshal(data, &l.MatchLabels)
+}
+
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// SortKey orders the rows of a table by a column
+type SortKey struct {
+	// Column is the header of the column to sort by
+	Column string `json:"column" yaml:"column"`
+	// Order is either asc or desc. Defaults to asc.
+	Order string `json:"order" yaml:"order"`
+}
+
+// ColumnWidthAuto sizes a column to fit its values
+const ColumnWidthAuto ColumnWidth = -1
+
+// ColumnWidth is the width of a column in characters, or auto
+type ColumnWidth int
+
+func (w *ColumnWidth) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s != "auto" {
+			return fmt.Errorf("unknown column width %q, must be a number or %q", s, "auto")
+		}
+		*w = ColumnWidthAuto
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("column width must be a number or %q: %w", "auto", err)
+	}
+	*w = ColumnWidth(n)
+	return nil
 }
 
 type Column struct {
-	Header string `json:"header" yaml:"header"`
-	Width  int    `json:"width" yaml:"width"`
-	Path   string `json:"path" yaml:"path"`
+	Header string      `json:"header" yaml:"header"`
+	Width  ColumnWidth `json:"width" yaml:"width"`
+	// MaxWidth caps the width of a column sized
+	// to fit its values. Defaults to 40.
+	MaxWidth int    `json:"maxWidth" yaml:"maxWidth"`
+	Path     string `json:"path" yaml:"path"`
+	// Expr is a CEL expression that computes the value of the
+	// column from the object, available as self. Used instead of Path.
+	Expr string `json:"expr" yaml:"expr"`
+	// Format changes how the values of the column are
+	// shown, i.e age shows timestamps as 5m or 3d4h
+	Format string `json:"format" yaml:"format"`
+	// Placeholder is shown for objects that don't have
+	// the path of the column. Defaults to n/a.
+	Placeholder string `json:"placeholder" yaml:"placeholder"`
+	// Styles style the values of the column. The
+	// first rule the value matches is applied.
+	Styles []StyleRule `json:"styles" yaml:"styles"`
+}
+
+// StyleRule styles a value when it meets every condition of the rule.
+// Colors are the names of colors in the palette of the theme.
+type StyleRule struct {
+	// Equals matches values equal to it
+	Equals string `json:"equals" yaml:"equals"`
+	// Matches matches values that match the regular expression
+	Matches string `json:"matches" yaml:"matches"`
+	// LessThan and GreaterThan match numbers and
+	// quantities, i.e 500m or 2Gi, less or more than them
+	LessThan    *resource.Quantity `json:"lessThan" yaml:"lessThan"`
+	GreaterThan *resource.Quantity `json:"greaterThan" yaml:"greaterThan"`
+
+	Color      string `json:"color" yaml:"color"`
+	Background string `json:"background" yaml:"background"`
+	Bold       bool   `json:"bold" yaml:"bold"`
+	Italic     bool   `json:"italic" yaml:"italic"`
+	Faint      bool   `json:"faint" yaml:"faint"`
+}
+
+// RowStyleRule styles a whole row when the value
+// at the path of the object in the row matches
+type RowStyleRule struct {
+	// Path is a dot notation path, like the path of a column
+	Path string `json:"path" yaml:"path"`
+	StyleRule
 }
 
 type Item struct {
@@ -58,4 +199,11 @@ type Logs struct {
 	PanelBase
 	Key       types.NamespacedName `json:"key" yaml:"key"`
 	Container string               `json:"container" yaml:"container"`
+	// PauseWhenHidden stops streaming logs while the panel isn't visible.
+	// When the panel is shown again, streaming resumes from when it was hidden.
+	PauseWhenHidden bool `json:"pauseWhenHidden" yaml:"pauseWhenHidden"`
+	// WarningPattern and ErrorPattern are regular expressions that
+	// raise the attention of the panel's tab when a new log line matches
+	WarningPattern string `json:"warningPattern" yaml:"warningPattern"`
+	ErrorPattern   string `json:"errorPattern" yaml:"errorPattern"`
 }
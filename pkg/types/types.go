@@ -2,4 +2,32 @@ package types
 
 type Dashboard struct {
 	Panels []Panel `json:"panels" yaml:"panels"`
+	// Start is the default start mode for all panels.
+	// Panels can override this with their own start mode.
+	Start string `json:"start" yaml:"start"`
+	// Layouts group multiple panels into a single tab
+	Layouts []Layout `json:"layout" yaml:"layout"`
+}
+
+// Layout is a tab that shows multiple panels
+// at once by arranging them in rows and columns
+type Layout struct {
+	Name string      `json:"name" yaml:"name"`
+	Rows []LayoutRow `json:"rows" yaml:"rows"`
+	// Section is the name of the section of
+	// the dashboard the layout is shown in
+	Section string `json:"section" yaml:"section"`
+}
+
+type LayoutRow struct {
+	// Ratio is the share of the tab height given to the row
+	Ratio   int            `json:"ratio" yaml:"ratio"`
+	Columns []LayoutColumn `json:"columns" yaml:"columns"`
+}
+
+type LayoutColumn struct {
+	// Panel is the name of the panel to show in the column
+	Panel string `json:"panel" yaml:"panel"`
+	// Ratio is the share of the row width given to the column
+	Ratio int `json:"ratio" yaml:"ratio"`
 }